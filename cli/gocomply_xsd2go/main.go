@@ -1,10 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/gocomply/xsd2go/pkg/xsd"
 	"github.com/gocomply/xsd2go/pkg/xsd2go"
 	"github.com/urfave/cli"
 )
@@ -39,13 +43,70 @@ var convert = cli.Command{
 					1)
 			}
 		}
+
+		switch c.String("diagnostics-format") {
+		case "text", "json":
+		default:
+			return cli.NewExitError(
+				fmt.Sprintf("Invalid diagnostics-format: '%s', expecting text or json", c.String("diagnostics-format")),
+				1)
+		}
 		return nil
 	},
 	Action: func(c *cli.Context) error {
 		xsdFile, goModule, outputDir := c.Args()[0], c.Args()[1], c.Args()[2]
-		err := xsd2go.Convert(xsdFile, goModule, outputDir, c.StringSlice("xmlns-override"))
+		var fetcher xsd.Fetcher
+		if c.Bool("allow-remote") || c.Bool("offline") {
+			cacheDir := c.String("cache-dir")
+			if cacheDir == "" {
+				userCacheDir, err := os.UserCacheDir()
+				if err != nil {
+					return cli.NewExitError(fmt.Sprintf("Cannot determine cache directory, provide --cache-dir: %v", err), 1)
+				}
+				cacheDir = filepath.Join(userCacheDir, "xsd2go")
+			}
+			fetcher = &xsd.HTTPFetcher{CacheDir: cacheDir, Offline: c.Bool("offline"), Timeout: c.Duration("fetch-timeout")}
+		}
+		schemaLocations := map[string]string{}
+		for _, location := range c.StringSlice("schema-location") {
+			idx := strings.LastIndex(location, "=")
+			if idx == -1 {
+				return cli.NewExitError(fmt.Sprintf("Invalid --schema-location '%s', expected NAMESPACE=FILE", location), 1)
+			}
+			schemaLocations[location[:idx]] = location[idx+1:]
+		}
+		res, err := xsd2go.NewGenerator(xsd2go.Options{
+			GoModule:        goModule,
+			OutputDir:       outputDir,
+			XmlnsOverrides:  c.StringSlice("xmlns-override"),
+			Catalogs:        c.StringSlice("catalog"),
+			SchemaLocations: schemaLocations,
+			SchemaPath:      c.StringSlice("schema-path"),
+			TimeTypes:       c.Bool("time-types"),
+			PreciseNumbers:  c.Bool("precise-numbers"),
+			BinaryTypes:     c.Bool("binary-types"),
+			QNameTypes:      c.Bool("qname-types"),
+			Fetcher:         fetcher,
+			Logger:          slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: omitTime})),
+			Output:          xsd2go.DirWriter(outputDir),
+		}).Generate(xsdFile)
+		diagnostics := res.Diagnostics
+		if err != nil && !containsErrors(diagnostics) {
+			diagnostics = append(diagnostics, xsd.Diagnostic{Severity: xsd.SeverityError, Message: err.Error()})
+		}
+
+		if c.String("diagnostics-format") == "json" {
+			if encErr := json.NewEncoder(os.Stderr).Encode(diagnostics); encErr != nil {
+				return cli.NewExitError(encErr, 1)
+			}
+		} else {
+			for _, diag := range diagnostics {
+				fmt.Fprintln(os.Stderr, diag)
+			}
+		}
 		if err != nil {
-			return cli.NewExitError(err, 1)
+			// The errors were reported above
+			return cli.NewExitError("", 1)
 		}
 		return nil
 	},
@@ -54,5 +115,71 @@ var convert = cli.Command{
 			Name:  "xmlns-override",
 			Usage: "Allows to explicitly set gopackage name for given XMLNS. Example: --xmlns-override='http://www.w3.org/2000/09/xmldsig#=xml_signatures'",
 		},
+		cli.StringSliceFlag{
+			Name:  "catalog",
+			Usage: "OASIS XML Catalog file mapping schemaLocation URLs and namespaces of imported schemas to local files. May be given repeatedly",
+		},
+		cli.StringSliceFlag{
+			Name:  "schema-path",
+			Usage: "Directory searched for the schemas of namespaces imported without schemaLocation, by their targetNamespace. May be given repeatedly",
+		},
+		cli.StringSliceFlag{
+			Name:  "schema-location",
+			Usage: "Schema file of namespace imported without schemaLocation, in form of NAMESPACE=FILE. May be given repeatedly",
+		},
+		cli.BoolFlag{
+			Name:  "time-types",
+			Usage: "Generate xsd:dateTime, xsd:date, xsd:time and xsd:g* types as types of xsdtypes package, instead of string",
+		},
+		cli.BoolFlag{
+			Name:  "precise-numbers",
+			Usage: "Generate xsd:decimal and unbounded xsd:integer types as arbitrary precision types of xsdtypes package, instead of float64 and int64",
+		},
+		cli.BoolFlag{
+			Name:  "binary-types",
+			Usage: "Generate xsd:base64Binary and xsd:hexBinary as byte slices of xsdtypes package, decoding the data, instead of string",
+		},
+		cli.BoolFlag{
+			Name:  "qname-types",
+			Usage: "Generate xsd:QName as type of xsdtypes package, resolving the prefix to namespace, instead of string",
+		},
+		cli.BoolFlag{
+			Name:  "allow-remote",
+			Usage: "Download schemas given by http(s) schemaLocation, keeping them in the cache directory",
+		},
+		cli.BoolFlag{
+			Name:  "offline",
+			Usage: "Load schemas given by http(s) schemaLocation from the cache directory only, failing if these are missing",
+		},
+		cli.DurationFlag{
+			Name:  "fetch-timeout",
+			Value: xsd.DefaultFetchTimeout,
+			Usage: "Time limit of downloading a single schema",
+		},
+		cli.StringFlag{
+			Name:  "cache-dir",
+			Usage: "Cache directory of the downloaded schemas (default: xsd2go within the user cache directory)",
+		},
+		cli.StringFlag{
+			Name:  "diagnostics-format",
+			Value: "text",
+			Usage: "Format of errors and warnings found in the schemas, written to stderr: text or json",
+		},
 	},
 }
+
+func omitTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+func containsErrors(diagnostics []xsd.Diagnostic) bool {
+	for _, diag := range diagnostics {
+		if diag.Severity == xsd.SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrUnsupported is returned by the generated clients for operations their binding leaves out, for instance
+// operations of rpc style.
+var ErrUnsupported = errors.New("operation is not supported by the binding")
+
+// Client calls operations of SOAP service over HTTP.
+type Client struct {
+	URL        string       // address of the service endpoint
+	HTTPClient *http.Client // http.DefaultClient if nil
+	Version    Version
+}
+
+// Call sends the request body of the operation and decodes the response body into response, which is left
+// untouched by one-way operations. Faults reported by the service are returned as *Fault.
+func (c *Client) Call(ctx context.Context, op *Operation, request, response any) error {
+	data, err := c.Version.encodeEnvelope(op.Request, request)
+	if err != nil {
+		return fmt.Errorf("could not encode request of %s: %w", op.Request.Local, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", c.Version.contentType(op.Action))
+	if c.Version != V12 {
+		req.Header.Set("SOAPAction", fmt.Sprintf("%q", op.Action))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("SOAP call of %s failed: %s", op.Request.Local, resp.Status)
+		}
+		if op.Response.Local != "" {
+			return fmt.Errorf("SOAP call of %s returned empty response", op.Request.Local)
+		}
+		return nil
+	}
+	var target any
+	if op.Response.Local != "" {
+		target = response
+	}
+	found, err := c.Version.decodeEnvelope(bytes.NewReader(body), target, op.Faults)
+	if err != nil {
+		var fault *Fault
+		if !errors.As(err, &fault) && resp.StatusCode/100 != 2 {
+			return fmt.Errorf("SOAP call of %s failed: %s", op.Request.Local, resp.Status)
+		}
+		return err
+	}
+	if !found && op.Response.Local != "" {
+		return fmt.Errorf("SOAP call of %s returned empty response body", op.Request.Local)
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+// Package soap provides runtime support for the SOAP stubs generated by xsd2go from WSDL documents.
+//
+// Generated clients and handlers exchange the document/literal messages, modelled by the generated
+// types, in SOAP 1.1 or SOAP 1.2 envelopes over HTTP.
+package soap
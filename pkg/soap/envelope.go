@@ -0,0 +1,107 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Version of the SOAP protocol the binding uses.
+type Version string
+
+const (
+	V11 Version = "1.1"
+	V12 Version = "1.2"
+)
+
+const (
+	envelope11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	envelope12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+func (v Version) namespace() string {
+	if v == V12 {
+		return envelope12Namespace
+	}
+	return envelope11Namespace
+}
+
+func (v Version) contentType(action string) string {
+	if v == V12 {
+		if action == "" {
+			return "application/soap+xml; charset=utf-8"
+		}
+		return fmt.Sprintf("application/soap+xml; charset=utf-8; action=%q", action)
+	}
+	return "text/xml; charset=utf-8"
+}
+
+// Operation describes document/literal operation of SOAP binding.
+type Operation struct {
+	Action   string   // SOAPAction of the operation
+	Request  xml.Name // element of the request body
+	Response xml.Name // element of the response body, empty for one-way operations
+	Faults   Faults   // types of fault details the operation declares
+}
+
+// encodeEnvelope wraps the body element, given by name, into SOAP envelope.
+func (v Version) encodeEnvelope(name xml.Name, body any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<soap:Envelope xmlns:soap="%s"><soap:Body>`, v.namespace())
+	if body != nil {
+		e := xml.NewEncoder(&buf)
+		if err := e.EncodeElement(body, xml.StartElement{Name: name}); err != nil {
+			return nil, err
+		}
+		if err := e.Flush(); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString(`</soap:Body></soap:Envelope>`)
+	return buf.Bytes(), nil
+}
+
+// decodeEnvelope decodes the body element of SOAP envelope into body, or returns *Fault if the envelope carries
+// one. Nil body skips the element, it returns false if the body is empty.
+func (v Version) decodeEnvelope(r io.Reader, body any, faults Faults) (bool, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return false, fmt.Errorf("could not read SOAP envelope: %w", err)
+	}
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	inBody := false
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return false, fmt.Errorf("could not decode SOAP envelope: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !inBody {
+				if t.Name.Local == "Envelope" && t.Name.Space != v.namespace() {
+					return false, fmt.Errorf("%w: envelope of namespace '%s'", ErrVersionMismatch, t.Name.Space)
+				}
+				inBody = t.Name == xml.Name{Space: v.namespace(), Local: "Body"}
+				continue
+			}
+			if t.Name == (xml.Name{Space: v.namespace(), Local: "Fault"}) {
+				return true, v.decodeFault(d, t, raw, faults)
+			}
+			if body == nil {
+				return true, d.Skip()
+			}
+			return true, d.DecodeElement(body, &t)
+		case xml.EndElement:
+			if inBody {
+				// Empty body
+				return false, nil
+			}
+		}
+	}
+}
+
+// ErrVersionMismatch is returned when the envelope received belongs to another SOAP version than the binding.
+var ErrVersionMismatch = errors.New("SOAP version mismatch")
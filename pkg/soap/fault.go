@@ -0,0 +1,187 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Fault is the error reported by SOAP service. Detail holds the decoded fault detail, if its element is declared
+// by the operation, DetailXML holds the detail as received.
+type Fault struct {
+	Code      string // faultcode of SOAP 1.1, Code/Value of SOAP 1.2
+	Reason    string // faultstring of SOAP 1.1, Reason/Text of SOAP 1.2
+	Actor     string // faultactor of SOAP 1.1, Role of SOAP 1.2
+	Detail    any
+	DetailXML string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("SOAP fault %s: %s", f.Code, f.Reason)
+}
+
+// Faults maps elements of fault details to factories of the generated types.
+type Faults map[xml.Name]func() any
+
+func (faults Faults) new(name xml.Name) (any, bool) {
+	factory, found := faults[name]
+	if !found {
+		// Fallback for services that do not qualify the detail by namespace
+		for registered, f := range faults {
+			if registered.Local == name.Local && (name.Space == "" || registered.Space == "") {
+				factory, found = f, true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// nameOf returns element of the fault detail of given type.
+func (faults Faults) nameOf(detail any) (xml.Name, bool) {
+	for name, factory := range faults {
+		if reflect.TypeOf(factory()) == reflect.TypeOf(detail) {
+			return name, true
+		}
+	}
+	return xml.Name{}, false
+}
+
+type faultElement struct {
+	Code11   string      `xml:"faultcode"`
+	String11 string      `xml:"faultstring"`
+	Actor11  string      `xml:"faultactor"`
+	Detail11 faultDetail `xml:"detail"`
+	Code12   string      `xml:"Code>Value"`
+	Reason12 string      `xml:"Reason>Text"`
+	Role12   string      `xml:"Role"`
+	Detail12 faultDetail `xml:"Detail"`
+}
+
+// faultDetail decodes the first child of fault detail by the decoder of the envelope, so that the namespaces
+// declared by its ancestors are known. The detail is also kept as received, sliced out of the raw envelope.
+type faultDetail struct {
+	faults Faults
+	raw    []byte
+	value  any
+	text   string
+}
+
+func (fd *faultDetail) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	begin := d.InputOffset()
+	first := true
+	for {
+		end := d.InputOffset()
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			detail, found := fd.faults.new(t.Name)
+			if first && found {
+				err = d.DecodeElement(detail, &t)
+				fd.value = detail
+			} else {
+				err = d.Skip()
+			}
+			if err != nil {
+				return fmt.Errorf("could not decode SOAP fault detail: %w", err)
+			}
+			first = false
+		case xml.EndElement:
+			fd.text = string(fd.raw[begin:end])
+			return nil
+		}
+	}
+}
+
+// decodeFault decodes fault element of the envelope given by raw data, which the decoder reads.
+func (v Version) decodeFault(d *xml.Decoder, start xml.StartElement, raw []byte, faults Faults) error {
+	fe := faultElement{
+		Detail11: faultDetail{faults: faults, raw: raw},
+		Detail12: faultDetail{faults: faults, raw: raw},
+	}
+	if err := d.DecodeElement(&fe, &start); err != nil {
+		return fmt.Errorf("could not decode SOAP fault: %w", err)
+	}
+	if v == V12 {
+		return &Fault{Code: fe.Code12, Reason: fe.Reason12, Actor: fe.Role12, Detail: fe.Detail12.value, DetailXML: fe.Detail12.text}
+	}
+	return &Fault{Code: fe.Code11, Reason: fe.String11, Actor: fe.Actor11, Detail: fe.Detail11.value, DetailXML: fe.Detail11.text}
+}
+
+// encodeFault wraps the fault into SOAP envelope. Known fault detail is encoded as element of its namespace.
+func (v Version) encodeFault(fault *Fault, faults Faults) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<soap:Envelope xmlns:soap="%s"><soap:Body><soap:Fault>`, v.namespace())
+	detailTag := "detail"
+	if v == V12 {
+		buf.WriteString(`<soap:Code>`)
+		writeText(&buf, "soap:Value", fault.Code)
+		buf.WriteString(`</soap:Code><soap:Reason>`)
+		writeText(&buf, `soap:Text xml:lang="en"`, fault.Reason)
+		buf.WriteString(`</soap:Reason>`)
+		if fault.Actor != "" {
+			writeText(&buf, "soap:Role", fault.Actor)
+		}
+		detailTag = "soap:Detail"
+	} else {
+		writeText(&buf, "faultcode", fault.Code)
+		writeText(&buf, "faultstring", fault.Reason)
+		if fault.Actor != "" {
+			writeText(&buf, "faultactor", fault.Actor)
+		}
+	}
+	if fault.Detail != nil || fault.DetailXML != "" {
+		buf.WriteString("<" + detailTag + ">")
+		if fault.Detail != nil {
+			e := xml.NewEncoder(&buf)
+			var err error
+			if name, found := faults.nameOf(fault.Detail); found {
+				err = e.EncodeElement(fault.Detail, xml.StartElement{Name: name})
+			} else {
+				err = e.Encode(fault.Detail)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := e.Flush(); err != nil {
+				return nil, err
+			}
+		} else {
+			buf.WriteString(fault.DetailXML)
+		}
+		buf.WriteString("</" + detailTag + ">")
+	}
+	buf.WriteString(`</soap:Fault></soap:Body></soap:Envelope>`)
+	return buf.Bytes(), nil
+}
+
+// writeText writes element of escaped text content, the start tag may carry attributes.
+func writeText(buf *bytes.Buffer, tag, text string) {
+	buf.WriteString("<" + tag + ">")
+	_ = xml.EscapeText(buf, []byte(text))
+	buf.WriteString("</" + strings.Fields(tag)[0] + ">")
+}
+
+// senderFault reports malformed request, receiverFault reports failure of the service itself.
+func (v Version) senderFault(reason string) *Fault {
+	if v == V12 {
+		return &Fault{Code: "soap:Sender", Reason: reason}
+	}
+	return &Fault{Code: "soap:Client", Reason: reason}
+}
+
+func (v Version) receiverFault(reason string) *Fault {
+	if v == V12 {
+		return &Fault{Code: "soap:Receiver", Reason: reason}
+	}
+	return &Fault{Code: "soap:Server", Reason: reason}
+}
@@ -0,0 +1,137 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Handler serves SOAP operations over HTTP. The requests are dispatched to the operations by element of their
+// body.
+type Handler struct {
+	version    Version
+	operations map[xml.Name]handlerOperation
+}
+
+type handlerOperation struct {
+	op     *Operation
+	invoke func(ctx context.Context, d *xml.Decoder, start xml.StartElement) (any, error)
+}
+
+// NewHandler creates handler of the binding of given SOAP version, its operations are added by Handle
+// and HandleOneWay.
+func NewHandler(version Version) *Handler {
+	return &Handler{version: version, operations: map[xml.Name]handlerOperation{}}
+}
+
+// Handle serves the request-response operation by the service method.
+func Handle[Req, Resp any](h *Handler, op *Operation, method func(context.Context, *Req) (*Resp, error)) {
+	h.operations[op.Request] = handlerOperation{op: op, invoke: func(ctx context.Context, d *xml.Decoder, start xml.StartElement) (any, error) {
+		req := new(Req)
+		if err := d.DecodeElement(req, &start); err != nil {
+			return nil, fmt.Errorf("%w: %v", errMalformedRequest, err)
+		}
+		resp, err := method(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}}
+}
+
+// HandleOneWay serves the one-way operation by the service method.
+func HandleOneWay[Req any](h *Handler, op *Operation, method func(context.Context, *Req) error) {
+	h.operations[op.Request] = handlerOperation{op: op, invoke: func(ctx context.Context, d *xml.Decoder, start xml.StartElement) (any, error) {
+		req := new(Req)
+		if err := d.DecodeElement(req, &start); err != nil {
+			return nil, fmt.Errorf("%w: %v", errMalformedRequest, err)
+		}
+		return nil, method(ctx, req)
+	}}
+}
+
+var errMalformedRequest = errors.New("malformed request")
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "SOAP requests are to be sent by POST", http.StatusMethodNotAllowed)
+		return
+	}
+	hop, resp, err := h.serve(r.Context(), r.Body)
+	if err != nil {
+		h.writeFault(w, hop.op, err)
+		return
+	}
+	if hop.op.Response.Local == "" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	data, err := h.version.encodeEnvelope(hop.op.Response, resp)
+	if err != nil {
+		h.writeFault(w, hop.op, err)
+		return
+	}
+	w.Header().Set("Content-Type", h.version.contentType(""))
+	_, _ = w.Write(data)
+}
+
+// serve decodes the envelope and invokes the operation given by its body element.
+func (h *Handler) serve(ctx context.Context, body io.Reader) (handlerOperation, any, error) {
+	d := xml.NewDecoder(body)
+	inBody := false
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return handlerOperation{}, nil, errMalformedRequest
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !inBody {
+			if start.Name.Local == "Envelope" && start.Name.Space != h.version.namespace() {
+				return handlerOperation{}, nil, ErrVersionMismatch
+			}
+			inBody = start.Name == xml.Name{Space: h.version.namespace(), Local: "Body"}
+			continue
+		}
+		hop, found := h.operations[start.Name]
+		if !found {
+			return handlerOperation{}, nil, h.version.senderFault("unknown operation " + start.Name.Local)
+		}
+		resp, err := hop.invoke(ctx, d, start)
+		return hop, resp, err
+	}
+}
+
+func (h *Handler) writeFault(w http.ResponseWriter, op *Operation, err error) {
+	var fault *Fault
+	switch {
+	case errors.As(err, &fault):
+	case errors.Is(err, errMalformedRequest), errors.Is(err, ErrVersionMismatch):
+		fault = h.version.senderFault(err.Error())
+	default:
+		fault = h.version.receiverFault(err.Error())
+	}
+	status := http.StatusInternalServerError
+	if h.version == V12 && fault.Code == h.version.senderFault("").Code {
+		// SOAP 1.2 HTTP binding tells faults of the sender apart
+		status = http.StatusBadRequest
+	}
+	var faults Faults
+	if op != nil {
+		faults = op.Faults
+	}
+	data, encErr := h.version.encodeFault(fault, faults)
+	if encErr != nil {
+		http.Error(w, encErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", h.version.contentType(""))
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,158 @@
+package soap_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/soap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type getQuote struct {
+	XMLName xml.Name `xml:"GetQuote"`
+	Symbol  string   `xml:"symbol"`
+}
+
+type getQuoteResponse struct {
+	XMLName xml.Name `xml:"GetQuoteResponse"`
+	Price   float64  `xml:"price"`
+}
+
+type notify struct {
+	XMLName xml.Name `xml:"Notify"`
+	Symbol  string   `xml:"symbol"`
+}
+
+type unknownSymbol struct {
+	XMLName xml.Name `xml:"UnknownSymbol"`
+	Symbol  string   `xml:"symbol"`
+}
+
+var getQuoteOperation = &soap.Operation{
+	Action:   "urn:GetQuote",
+	Request:  xml.Name{Space: "urn:example:quote", Local: "GetQuote"},
+	Response: xml.Name{Space: "urn:example:quote", Local: "GetQuoteResponse"},
+	Faults: soap.Faults{
+		{Space: "urn:example:quote", Local: "UnknownSymbol"}: func() any { return &unknownSymbol{} },
+	},
+}
+
+var notifyOperation = &soap.Operation{
+	Request: xml.Name{Space: "urn:example:quote", Local: "Notify"},
+}
+
+func quoteHandler(version soap.Version, notified *[]string) http.Handler {
+	h := soap.NewHandler(version)
+	soap.Handle(h, getQuoteOperation, func(_ context.Context, req *getQuote) (*getQuoteResponse, error) {
+		switch req.Symbol {
+		case "ACME":
+			return &getQuoteResponse{Price: 12.5}, nil
+		case "FAIL":
+			return nil, errors.New("quotes are not available")
+		}
+		return nil, &soap.Fault{Code: "soap:Client", Reason: "unknown symbol", Detail: &unknownSymbol{Symbol: req.Symbol}}
+	})
+	soap.HandleOneWay(h, notifyOperation, func(_ context.Context, req *notify) error {
+		*notified = append(*notified, req.Symbol)
+		return nil
+	})
+	return h
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, version := range []soap.Version{soap.V11, soap.V12} {
+		t.Run(string(version), func(t *testing.T) {
+			notified := []string{}
+			server := httptest.NewServer(quoteHandler(version, &notified))
+			defer server.Close()
+			client := &soap.Client{URL: server.URL, Version: version}
+
+			resp := &getQuoteResponse{}
+			require.NoError(t, client.Call(t.Context(), getQuoteOperation, &getQuote{Symbol: "ACME"}, resp))
+			assert.InDelta(t, 12.5, resp.Price, 0)
+
+			err := client.Call(t.Context(), getQuoteOperation, &getQuote{Symbol: "XYZ"}, resp)
+			var fault *soap.Fault
+			require.ErrorAs(t, err, &fault)
+			assert.Equal(t, "unknown symbol", fault.Reason)
+			assert.Equal(t, &unknownSymbol{XMLName: xml.Name{Space: "urn:example:quote", Local: "UnknownSymbol"}, Symbol: "XYZ"}, fault.Detail)
+
+			err = client.Call(t.Context(), getQuoteOperation, &getQuote{Symbol: "FAIL"}, resp)
+			require.ErrorAs(t, err, &fault)
+			assert.Equal(t, "quotes are not available", fault.Reason)
+			assert.Nil(t, fault.Detail)
+
+			require.NoError(t, client.Call(t.Context(), notifyOperation, &notify{Symbol: "ACME"}, nil))
+			assert.Equal(t, []string{"ACME"}, notified)
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	var body, action, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body, action, contentType = string(data), r.Header.Get("SOAPAction"), r.Header.Get("Content-Type")
+		_, _ = w.Write([]byte(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+<q:GetQuoteResponse xmlns:q="urn:example:quote"><q:price>1</q:price></q:GetQuoteResponse></s:Body></s:Envelope>`))
+	}))
+	defer server.Close()
+
+	resp := &getQuoteResponse{}
+	client := &soap.Client{URL: server.URL, Version: soap.V11}
+	require.NoError(t, client.Call(t.Context(), getQuoteOperation, &getQuote{Symbol: "ACME"}, resp))
+	assert.Contains(t, body, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>`+
+		`<GetQuote xmlns="urn:example:quote"><symbol>ACME</symbol></GetQuote>`)
+	assert.Equal(t, `"urn:GetQuote"`, action)
+	assert.Equal(t, "text/xml; charset=utf-8", contentType)
+	assert.InDelta(t, 1.0, resp.Price, 0)
+
+	client.Version = soap.V12
+	err := client.Call(t.Context(), getQuoteOperation, &getQuote{Symbol: "ACME"}, resp)
+	require.ErrorIs(t, err, soap.ErrVersionMismatch)
+	assert.True(t, strings.HasPrefix(contentType, "application/soap+xml"))
+	assert.Contains(t, contentType, `action="urn:GetQuote"`)
+}
+
+func TestFaultNamespaces(t *testing.T) {
+	// Prefix of the fault detail is declared by the envelope, not by the detail itself
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" xmlns:q="urn:example:quote">` +
+			`<s:Body><s:Fault><faultcode>s:Client</faultcode><faultstring>unknown symbol</faultstring>` +
+			`<detail><q:UnknownSymbol><q:symbol>XYZ</q:symbol></q:UnknownSymbol></detail></s:Fault></s:Body></s:Envelope>`))
+	}))
+	defer server.Close()
+
+	client := &soap.Client{URL: server.URL, Version: soap.V11}
+	err := client.Call(t.Context(), getQuoteOperation, &getQuote{Symbol: "XYZ"}, &getQuoteResponse{})
+	var fault *soap.Fault
+	require.ErrorAs(t, err, &fault)
+	assert.Equal(t, "s:Client", fault.Code)
+	assert.Equal(t, &unknownSymbol{XMLName: xml.Name{Space: "urn:example:quote", Local: "UnknownSymbol"}, Symbol: "XYZ"}, fault.Detail)
+	assert.Equal(t, `<q:UnknownSymbol><q:symbol>XYZ</q:symbol></q:UnknownSymbol>`, fault.DetailXML)
+}
+
+func TestHandlerRejectsUnknownOperation(t *testing.T) {
+	server := httptest.NewServer(quoteHandler(soap.V12, &[]string{}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, strings.NewReader(
+		`<e:Envelope xmlns:e="http://www.w3.org/2003/05/soap-envelope"><e:Body><Unknown/></e:Body></e:Envelope>`))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, string(data), "<soap:Value>soap:Sender</soap:Value>")
+	assert.Contains(t, string(data), "unknown operation Unknown")
+}
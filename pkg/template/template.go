@@ -15,42 +15,70 @@ import (
 //go:embed types.tmpl
 var templText string
 
+//go:embed service.tmpl
+var serviceTemplText string
+
+// FileName is the name of go file generated for each schema.
+const FileName = "models.go"
+
+// ServiceFileName is the name of go file holding SOAP stubs generated for WSDL.
+const ServiceFileName = "service.go"
+
+// GenerateTypes writes go code of the schema into its package directory within the output directory.
 func GenerateTypes(schema *xsd.Schema, outputDir string) error {
-	t, err := newTemplate()
+	p, err := RenderTypes(schema)
 	if err != nil {
 		return err
 	}
 
-	packageName := schema.GoPackageName()
-	dir := filepath.Join(outputDir, packageName)
+	dir := filepath.Join(outputDir, schema.GoPackageName())
 	err = os.MkdirAll(dir, os.FileMode(0722))
 	if err != nil {
 		return err
 	}
-	goFile := filepath.Clean(filepath.Join(dir, "models.go"))
-	fmt.Printf("\tGenerating '%s'\n", goFile)
-	f, err := os.Create(goFile)
-	if err != nil {
+	goFile := filepath.Clean(filepath.Join(dir, FileName))
+	if err := os.WriteFile(goFile, p, 0o666); err != nil {
 		return fmt.Errorf("could not create '%s': %w", goFile, err)
 	}
-	defer f.Close()
+	return nil
+}
+
+// RenderTypes returns formatted go code of the schema.
+func RenderTypes(schema *xsd.Schema) ([]byte, error) {
+	t, err := newTemplate()
+	if err != nil {
+		return nil, err
+	}
 
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, schema); err != nil {
-		return fmt.Errorf("could not execute template: %w", err)
+		return nil, fmt.Errorf("could not execute template: %w", err)
 	}
 
 	p, err := format.Source(buf.Bytes())
 	if err != nil {
-		return fmt.Errorf("unable to gofmt output file %s, error: %w", buf.String(), err)
+		return nil, fmt.Errorf("unable to gofmt output file %s, error: %w", buf.String(), err)
 	}
+	return p, nil
+}
 
-	_, err = f.Write(p)
+// RenderService returns formatted go code of SOAP stubs of the WSDL service.
+func RenderService(svc *xsd.Service) ([]byte, error) {
+	t, err := template.New("service.tmpl").Parse(serviceTemplText)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, svc); err != nil {
+		return nil, fmt.Errorf("could not execute template: %w", err)
+	}
+
+	p, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to gofmt output file %s, error: %w", buf.String(), err)
+	}
+	return p, nil
 }
 
 func newTemplate() (*template.Template, error) {
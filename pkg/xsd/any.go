@@ -0,0 +1,156 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Any defines wildcard allowing elements not declared by the schema (xsd:any).
+type Any struct {
+	XMLName         xml.Name    `xml:"http://www.w3.org/2001/XMLSchema any"`
+	Namespace       string      `xml:"namespace,attr"`
+	ProcessContents string      `xml:"processContents,attr"`
+	MinOccurs       string      `xml:"minOccurs,attr"`
+	MaxOccurs       string      `xml:"maxOccurs,attr"`
+	Annotation      *Annotation `xml:"annotation"`
+	schema          *Schema
+}
+
+func (a *Any) compile(sch *Schema) {
+	a.schema = sch
+}
+
+// Wildcard is represented by golang struct field that collects all the unknown child elements.
+func (a *Any) element() Element {
+	return Element{Annotation: a.Annotation, wildcard: a}
+}
+
+// AnyAttribute defines wildcard allowing attributes not declared by the schema (xsd:anyAttribute).
+type AnyAttribute struct {
+	XMLName         xml.Name    `xml:"http://www.w3.org/2001/XMLSchema anyAttribute"`
+	Namespace       string      `xml:"namespace,attr"`
+	ProcessContents string      `xml:"processContents,attr"`
+	Annotation      *Annotation `xml:"annotation"`
+	schema          *Schema
+}
+
+func (a *AnyAttribute) compile(sch *Schema) {
+	a.schema = sch
+}
+
+// Wildcard is represented by golang struct field that collects all the unknown attributes.
+func (a *AnyAttribute) attribute() Attribute {
+	return Attribute{Annotation: a.Annotation, wildcard: a}
+}
+
+func wildcardDocumentation(kind, namespace, processContents string) string {
+	if namespace == "" {
+		namespace = "##any"
+	}
+	if processContents == "" {
+		processContents = "strict"
+	}
+	return fmt.Sprintf("%s matching the wildcard (namespace=%s, processContents=%s)", kind, namespace, processContents)
+}
+
+// Multiple wildcards within one type are all collected by a single golang struct field.
+func deduplicateWildcards(elements []Element) []Element {
+	first := -1
+	result := make([]Element, 0, len(elements))
+	for _, element := range elements {
+		if element.wildcard != nil {
+			if first != -1 {
+				result[first].wildcard = result[first].wildcard.union(element.wildcard)
+				continue
+			}
+			first = len(result)
+		}
+		result = append(result, element)
+	}
+	return result
+}
+
+// Multiple attribute wildcards of one type (e.g. of base type and of the extension) are all collected by a single
+// golang struct field.
+func deduplicateAttributeWildcards(attributes []Attribute) []Attribute {
+	first := -1
+	result := make([]Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		if attribute.wildcard != nil {
+			if first != -1 {
+				wildcard := *result[first].wildcard
+				wildcard.Namespace = unionNamespaces(wildcard.namespace(), attribute.wildcard.namespace())
+				result[first].wildcard = &wildcard
+				continue
+			}
+			first = len(result)
+		}
+		result = append(result, attribute)
+	}
+	return result
+}
+
+func (a *Any) union(other *Any) *Any {
+	res := *a
+	res.Namespace = unionNamespaces(a.namespace(), other.namespace())
+	return &res
+}
+
+func unionNamespaces(namespace, other string) string {
+	if namespace == "##any" || other == "##any" {
+		return "##any"
+	}
+	namespaces := strings.Fields(namespace)
+	for _, ns := range strings.Fields(other) {
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return strings.Join(namespaces, " ")
+}
+
+func (a *Any) namespace() string {
+	if a.Namespace == "" {
+		return "##any"
+	}
+	return a.Namespace
+}
+
+func (a *AnyAttribute) namespace() string {
+	if a.Namespace == "" {
+		return "##any"
+	}
+	return a.Namespace
+}
+
+// WildcardNamespace returns namespace constraint of the wildcard collected by this field, or empty string when
+// the field is not a wildcard or it allows any namespace.
+func (e *Element) WildcardNamespace() string {
+	if e.wildcard == nil || e.wildcard.namespace() == "##any" {
+		return ""
+	}
+	return e.wildcard.namespace()
+}
+
+// WildcardTargetNamespace returns target namespace of the schema declaring the wildcard, which its namespace
+// constraint refers to.
+func (e *Element) WildcardTargetNamespace() string {
+	return e.wildcard.schema.targetNamespace()
+}
+
+// WildcardNamespace returns namespace constraint of the wildcard collected by this field, or empty string when
+// the field is not a wildcard or it allows any namespace.
+func (a *Attribute) WildcardNamespace() string {
+	if a.wildcard == nil || a.wildcard.namespace() == "##any" {
+		return ""
+	}
+	return a.wildcard.namespace()
+}
+
+// WildcardTargetNamespace returns target namespace of the schema declaring the wildcard, which its namespace
+// constraint refers to.
+func (a *Attribute) WildcardTargetNamespace() string {
+	return a.wildcard.schema.targetNamespace()
+}
@@ -13,12 +13,15 @@ type Attribute struct {
 	Name           string      `xml:"name,attr"`
 	Type           reference   `xml:"type,attr"`
 	Use            string      `xml:"use,attr"`
+	Default        string      `xml:"default,attr"`
+	Fixed          string      `xml:"fixed,attr"`
 	Annotation     *Annotation `xml:"annotation"`
 	DuplicateCount uint        `xml:"-"`
 	Ref            reference   `xml:"ref,attr"`
 	refAttr        *Attribute
 	typ            Type
 	schema         *Schema
+	wildcard       *AnyAttribute
 }
 
 func (a *Attribute) ContainsDocumentation() bool {
@@ -26,6 +29,9 @@ func (a *Attribute) ContainsDocumentation() bool {
 }
 
 func (a *Attribute) Documentation() string {
+	if a.wildcard != nil && (a.Annotation == nil || len(a.Annotation.Documentations) == 0) {
+		return wildcardDocumentation("Attributes", a.wildcard.Namespace, a.wildcard.ProcessContents)
+	}
 	if a.Annotation == nil {
 		return ""
 	}
@@ -37,6 +43,9 @@ func (a *Attribute) Documentation() string {
 
 // Public Go Name of this struct item.
 func (a *Attribute) GoName() string {
+	if a.wildcard != nil {
+		return "AnyAttrs"
+	}
 	name := a.Name
 	if a.Name == "" {
 		name = a.Ref.GoName()
@@ -47,7 +56,14 @@ func (a *Attribute) GoName() string {
 	return strcase.ToCamel(name)
 }
 
+func (a *Attribute) GoFieldType() string {
+	return a.GoForeignModule() + a.GoType()
+}
+
 func (a *Attribute) GoType() string {
+	if a.wildcard != nil {
+		return "[]xml.Attr"
+	}
 	if a.typ == nil {
 		return "string"
 	}
@@ -82,6 +98,9 @@ func (a *Attribute) GoForeignModule() string {
 }
 
 func (a *Attribute) Modifiers() string {
+	if a.wildcard != nil {
+		return "any,attr"
+	}
 	res := "attr"
 	if a.optional() {
 		res += ",omitempty"
@@ -90,29 +109,57 @@ func (a *Attribute) Modifiers() string {
 }
 
 func (a *Attribute) XmlName() string {
+	if a.wildcard != nil {
+		return ""
+	}
 	if a.Name == "" {
 		return a.Ref.Name()
 	}
 	return a.Name
 }
 
+// DefaultValue returns the value this attribute takes when absent, as given either by default= or by fixed=.
+func (a *Attribute) DefaultValue() string {
+	if a.wildcard != nil {
+		return ""
+	}
+	if a.Default == "" && a.Fixed == "" && a.refAttr != nil {
+		return a.refAttr.DefaultValue()
+	}
+	if a.Fixed != "" {
+		return a.Fixed
+	}
+	return a.Default
+}
+
+// IsFixed reports whether the attribute must not take any other value than its DefaultValue.
+func (a *Attribute) IsFixed() bool {
+	if a.Default == "" && a.Fixed == "" && a.refAttr != nil {
+		return a.refAttr.IsFixed()
+	}
+	return a.Fixed != ""
+}
+
 func (a *Attribute) optional() bool {
 	// 'use' defaults to 'optional': https://www.w3.org/TR/xmlschema11-1/#declare-attribute
 	return a.Use == "" || a.Use == "optional"
 }
 
-func (a *Attribute) compile(s *Schema) {
+func (a *Attribute) compile(s *Schema) error {
 	a.schema = s
 	if a.Ref != "" {
-		a.refAttr = a.schema.findReferencedAttribute(a.Ref)
-		if a.refAttr == nil {
-			panic("Cannot resolve attribute reference: " + a.Ref)
+		refAttr, err := a.schema.findReferencedAttribute(a.Ref)
+		if err != nil {
+			return err
 		}
+		a.refAttr = refAttr
 	}
 	if a.Type != "" && a.typ == nil {
-		a.typ = a.schema.findReferencedType(a.Type)
-		if a.typ == nil {
-			panic("Cannot resolve attribute type: " + a.Type)
+		typ, err := a.schema.findReferencedType(a.Type)
+		if err != nil {
+			return err
 		}
+		a.typ = typ
 	}
+	return nil
 }
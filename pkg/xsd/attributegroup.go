@@ -2,15 +2,17 @@ package xsd
 
 import (
 	"encoding/xml"
+	"errors"
 
 	"github.com/iancoleman/strcase"
 )
 
 type AttributeGroup struct {
-	XMLName          xml.Name    `xml:"http://www.w3.org/2001/XMLSchema attributeGroup"`
-	Name             string      `xml:"name,attr"`
-	Ref              reference   `xml:"ref,attr"`
-	AttributesDirect []Attribute `xml:"attribute"`
+	XMLName          xml.Name      `xml:"http://www.w3.org/2001/XMLSchema attributeGroup"`
+	Name             string        `xml:"name,attr"`
+	Ref              reference     `xml:"ref,attr"`
+	AttributesDirect []Attribute   `xml:"attribute"`
+	AnyAttribute     *AnyAttribute `xml:"anyAttribute"`
 	typ              Type
 	schema           *Schema
 }
@@ -20,33 +22,46 @@ func (att *AttributeGroup) Attributes() []Attribute {
 	if att.typ != nil {
 		attrs = append(attrs, att.typ.Attributes()...)
 	}
+	if att.AnyAttribute != nil {
+		attrs = append(attrs[:len(attrs):len(attrs)], att.AnyAttribute.attribute())
+	}
 	return attrs
 }
 
-func (att *AttributeGroup) compile(sch *Schema, parentElement *Element) {
+func (att *AttributeGroup) compile(sch *Schema, parentElement *Element) error {
 	att.schema = sch
+	var errs []error
+	if att.AnyAttribute != nil {
+		att.AnyAttribute.compile(sch)
+	}
 	if att.Ref != "" {
-		att.typ = sch.findReferencedType(att.Ref)
-		if att.typ == nil {
-			panic("Cannot build xsd:attributeGroup: unknown type: " + string(att.Ref))
+		typ, err := sch.compileReferencedType(att.Ref, parentElement)
+		if err != nil {
+			errs = append(errs, err)
 		}
-		att.typ.compile(sch, parentElement)
+		att.typ = typ
 	}
 
 	// Handle improbable name clash. Consider XSD defining two attributes on the element:
 	// "id" and "Id", this would create name clash given the camelization we do.
 	goNames := map[string]uint{}
-	for idx := range att.Attributes() {
-		attribute := &att.Attributes()[idx]
-		attribute.compile(sch)
+	for idx := range att.AttributesDirect {
+		attribute := &att.AttributesDirect[idx]
+		if err := attribute.compile(sch); err != nil {
+			errs = append(errs, within(indexedStep("attribute", idx), err))
+		}
 
 		count := goNames[attribute.GoName()]
 		count += 1
 		goNames[attribute.GoName()] = count
 		attribute.DuplicateCount = count
+		if count > 1 {
+			sch.warn("attribute '%s' clashes with another attribute once camelized, generated as %s", attribute.Name, attribute.GoName())
+		}
 		// Second GoName may be different depending on the DuplicateCount
 		goNames[attribute.GoName()] = count
 	}
+	return errors.Join(errs...)
 }
 
 func (att *AttributeGroup) GoName() string {
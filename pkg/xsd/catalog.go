@@ -0,0 +1,192 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// catalog maps URIs and system identifiers of the schemas to local files, as given by OASIS XML Catalog
+// (https://www.oasis-open.org/committees/download.php/14809/xml-catalogs.html). The uri, rewriteURI, system,
+// rewriteSystem and nextCatalog entries are supported.
+type catalog struct {
+	uris           map[string]string
+	rewriteURIs    []catalogRewrite
+	systems        map[string]string
+	rewriteSystems []catalogRewrite
+	next           []*catalog
+}
+
+type catalogRewrite struct {
+	prefix      string
+	replacement string
+}
+
+// resolve returns the local file given by the catalog for URI or system identifier.
+func (c *catalog) resolve(id string) (string, bool) {
+	if file, found := c.uris[id]; found {
+		return file, true
+	}
+	if file, found := rewrite(c.rewriteURIs, id); found {
+		return file, true
+	}
+	if file, found := c.systems[id]; found {
+		return file, true
+	}
+	if file, found := rewrite(c.rewriteSystems, id); found {
+		return file, true
+	}
+	for _, next := range c.next {
+		if file, found := next.resolve(id); found {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// rewrite uses the longest matching prefix, the first one given wins ties.
+func rewrite(rewrites []catalogRewrite, id string) (string, bool) {
+	var best *catalogRewrite
+	for idx := range rewrites {
+		rw := &rewrites[idx]
+		if strings.HasPrefix(id, rw.prefix) && (best == nil || len(rw.prefix) > len(best.prefix)) {
+			best = rw
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.replacement + strings.TrimPrefix(id, best.prefix), true
+}
+
+type catalogEntries struct {
+	Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	URIs []struct {
+		Name string `xml:"name,attr"`
+		URI  string `xml:"uri,attr"`
+		Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	} `xml:"uri"`
+	RewriteURIs []struct {
+		StartString   string `xml:"uriStartString,attr"`
+		RewritePrefix string `xml:"rewritePrefix,attr"`
+	} `xml:"rewriteURI"`
+	Systems []struct {
+		SystemId string `xml:"systemId,attr"`
+		URI      string `xml:"uri,attr"`
+		Base     string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	} `xml:"system"`
+	RewriteSystems []struct {
+		StartString   string `xml:"systemIdStartString,attr"`
+		RewritePrefix string `xml:"rewritePrefix,attr"`
+	} `xml:"rewriteSystem"`
+	NextCatalogs []struct {
+		Catalog string `xml:"catalog,attr"`
+	} `xml:"nextCatalog"`
+	Groups []catalogEntries `xml:"group"`
+}
+
+// loadCatalog reads the catalog file, and the catalogs it chains to, from the workspace filesystem.
+func (ws *Workspace) loadCatalog(file string, loaded map[string]*catalog) (*catalog, error) {
+	if c, found := loaded[file]; found {
+		return c, nil
+	}
+	data, err := fs.ReadFile(ws.fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read catalog: %w", err)
+	}
+	var entries catalogEntries
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = charset.NewReaderLabel
+	if err := d.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("error decoding catalog %s: %w", file, err)
+	}
+
+	c := &catalog{uris: map[string]string{}, systems: map[string]string{}}
+	loaded[file] = c
+	return c, ws.addCatalogEntries(c, entries, ws.dir(file), loaded)
+}
+
+func (ws *Workspace) addCatalogEntries(c *catalog, entries catalogEntries, base string, loaded map[string]*catalog) error {
+	base = ws.catalogTarget(base, entries.Base)
+	for _, uri := range entries.URIs {
+		if _, found := c.uris[uri.Name]; !found {
+			c.uris[uri.Name] = ws.catalogTarget(ws.catalogTarget(base, uri.Base), uri.URI)
+		}
+	}
+	for _, rw := range entries.RewriteURIs {
+		c.rewriteURIs = append(c.rewriteURIs, catalogRewrite{rw.StartString, ws.catalogTarget(base, rw.RewritePrefix)})
+	}
+	for _, system := range entries.Systems {
+		if _, found := c.systems[system.SystemId]; !found {
+			c.systems[system.SystemId] = ws.catalogTarget(ws.catalogTarget(base, system.Base), system.URI)
+		}
+	}
+	for _, rw := range entries.RewriteSystems {
+		c.rewriteSystems = append(c.rewriteSystems, catalogRewrite{rw.StartString, ws.catalogTarget(base, rw.RewritePrefix)})
+	}
+	for _, group := range entries.Groups {
+		if err := ws.addCatalogEntries(c, group, base, loaded); err != nil {
+			return err
+		}
+	}
+	for _, next := range entries.NextCatalogs {
+		nc, err := ws.loadCatalog(ws.catalogTarget(base, next.Catalog), loaded)
+		if err != nil {
+			return err
+		}
+		c.next = append(c.next, nc)
+	}
+	return nil
+}
+
+// catalogTarget resolves reference given by the catalog relative to its base. Local files may be referenced by
+// file: URIs, other absolute URIs are kept as they are.
+func (ws *Workspace) catalogTarget(base, ref string) string {
+	if ref == "" {
+		return base
+	}
+	if strings.HasPrefix(ref, "file://") {
+		return strings.TrimPrefix(ref, "file://")
+	}
+	if strings.Contains(ref, "://") || path.IsAbs(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	trailingSlash := strings.HasSuffix(ref, "/")
+	res := ws.resolveLocation(base, ref)
+	if trailingSlash {
+		// Rewrite prefixes denote directories
+		res += "/"
+	}
+	return res
+}
+
+// locate returns path of the document brought in by xsd:import, xsd:include or xsd:redefine. Catalogs are consulted
+// for schemaLocation first, then for the namespace of the import. Import without schemaLocation is looked up by
+// its namespace. Empty path is returned if there is no document to be loaded.
+func (ws *Workspace) locate(baseDir, schemaLocation, namespace string) string {
+	for _, id := range []string{schemaLocation, namespace} {
+		if id == "" {
+			continue
+		}
+		for _, c := range ws.catalogs {
+			if file, found := c.resolve(id); found {
+				return file
+			}
+		}
+	}
+	if schemaLocation != "" {
+		return ws.resolveLocation(baseDir, schemaLocation)
+	}
+	if namespace != "" {
+		if file, found := ws.locateNamespace(namespace); found {
+			return file
+		}
+	}
+	return ""
+}
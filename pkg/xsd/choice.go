@@ -2,6 +2,7 @@ package xsd
 
 import (
 	"encoding/xml"
+	"errors"
 )
 
 type Choice struct {
@@ -10,16 +11,21 @@ type Choice struct {
 	MaxOccurs   string     `xml:"maxOccurs,attr"`
 	ElementList []Element  `xml:"element"`
 	Sequences   []Sequence `xml:"sequence"`
+	Groups      []Group    `xml:"group"`
+	Any         []Any      `xml:"any"`
 	schema      *Schema
 	allElements []Element
 }
 
-func (c *Choice) compile(sch *Schema, parentElement *Element) {
+func (c *Choice) compile(sch *Schema, parentElement *Element) error {
 	c.schema = sch
+	var errs []error
 	for idx := range c.ElementList {
 		el := &c.ElementList[idx]
 
-		el.compile(sch, parentElement)
+		if err := el.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("element", idx), err))
+		}
 		// Propagate array cardinality downwards
 		if c.MaxOccurs == "unbounded" {
 			el.MaxOccurs = "unbounded"
@@ -33,7 +39,9 @@ func (c *Choice) compile(sch *Schema, parentElement *Element) {
 	inheritedElements := []Element{}
 	for idx := range c.Sequences {
 		el := &c.Sequences[idx]
-		el.compile(sch, parentElement)
+		if err := el.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("sequence", idx), err))
+		}
 		for _, el2 := range el.Elements() {
 			if c.MaxOccurs == "unbounded" {
 				el2.MaxOccurs = "unbounded"
@@ -44,8 +52,28 @@ func (c *Choice) compile(sch *Schema, parentElement *Element) {
 			inheritedElements = append(inheritedElements, el2)
 		}
 	}
+	for idx := range c.Groups {
+		grp := &c.Groups[idx]
+		if err := grp.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("group", idx), err))
+		}
+		for _, el2 := range grp.Elements() {
+			if c.MaxOccurs == "unbounded" {
+				el2.MaxOccurs = "unbounded"
+			}
+			if el2.MinOccurs == "" {
+				el2.MinOccurs = "0"
+			}
+			inheritedElements = append(inheritedElements, el2)
+		}
+	}
+	for idx := range c.Any {
+		c.Any[idx].compile(sch)
+		inheritedElements = append(inheritedElements, c.Any[idx].element())
+	}
 	// deduplicate elements that represent duplicate within xsd:choice/xsd:sequence structure
 	c.allElements = append(c.ElementList, deduplicateElements(inheritedElements)...)
+	return errors.Join(errs...)
 }
 
 func (c *Choice) Elements() []Element {
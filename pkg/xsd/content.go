@@ -2,13 +2,14 @@ package xsd
 
 import (
 	"encoding/xml"
+	"fmt"
 )
 
 type GenericContent interface {
 	Attributes() []Attribute
 	Elements() []Element
 	ContainsText() bool
-	compile(*Schema, *Element)
+	compile(*Schema, *Element) error
 }
 type SimpleContent struct {
 	XMLName     xml.Name     `xml:"http://www.w3.org/2001/XMLSchema simpleContent"`
@@ -37,14 +38,19 @@ func (sc *SimpleContent) Elements() []Element {
 	return []Element{}
 }
 
-func (sc *SimpleContent) compile(sch *Schema, parentElement *Element) {
+func (sc *SimpleContent) compile(sch *Schema, parentElement *Element) error {
 	sc.schema = sch
 	if sc.Extension != nil {
-		sc.Extension.compile(sch, parentElement)
+		if err := sc.Extension.compile(sch, parentElement); err != nil {
+			return within("extension", err)
+		}
 	}
 	if sc.Restriction != nil {
-		sc.Restriction.compile(sch, parentElement)
+		if err := sc.Restriction.compile(sch, parentElement); err != nil {
+			return within("restriction", err)
+		}
 	}
+	return nil
 }
 
 type ComplexContent struct {
@@ -74,15 +80,20 @@ func (cc *ComplexContent) ContainsText() bool {
 	return cc.Extension != nil && cc.Extension.ContainsText()
 }
 
-func (cc *ComplexContent) compile(sch *Schema, parentElement *Element) {
+func (cc *ComplexContent) compile(sch *Schema, parentElement *Element) error {
 	cc.schema = sch
 	if cc.Extension != nil {
-		cc.Extension.compile(sch, parentElement)
+		if err := cc.Extension.compile(sch, parentElement); err != nil {
+			return within("extension", err)
+		}
 	}
 	if cc.Restriction != nil {
 		if cc.Extension != nil {
-			panic("Not implemented: xsd:complexContent defines xsd:restriction and xsd:extension")
+			return fmt.Errorf("%w: xsd:complexContent defines xsd:restriction and xsd:extension", ErrNotImplemented)
+		}
+		if err := cc.Restriction.compile(sch, parentElement); err != nil {
+			return within("restriction", err)
 		}
-		cc.Restriction.compile(sch, parentElement)
 	}
+	return nil
 }
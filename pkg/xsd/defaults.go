@@ -0,0 +1,46 @@
+package xsd
+
+import "strings"
+
+// HasDefaultValues reports whether the golang type needs to apply default or fixed values given by the schema.
+func (e *Element) HasDefaultValues() bool {
+	return hasDefaultValues(e.Attributes(), e.Elements(), false)
+}
+
+// HasFixedValues reports whether the golang type needs to enforce fixed values given by the schema.
+func (e *Element) HasFixedValues() bool {
+	return hasDefaultValues(e.Attributes(), e.Elements(), true)
+}
+
+func (ct *ComplexType) HasDefaultValues() bool {
+	return hasDefaultValues(ct.Attributes(), ct.Elements(), false)
+}
+
+func (ct *ComplexType) HasFixedValues() bool {
+	return hasDefaultValues(ct.Attributes(), ct.Elements(), true)
+}
+
+func hasDefaultValues(attributes []Attribute, elements []Element, fixedOnly bool) bool {
+	for idx := range attributes {
+		if attributes[idx].DefaultValue() != "" && (!fixedOnly || attributes[idx].IsFixed()) {
+			return true
+		}
+	}
+	for idx := range elements {
+		if elements[idx].DefaultValue() != "" && (!fixedOnly || elements[idx].IsFixed()) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDefaultAccessor reports whether the golang type gets accessor of the element, which falls back to the default
+// value when the element is absent. Optional elements are left unset when absent, rather than given the default.
+func (e *Element) HasDefaultAccessor() bool {
+	return e.DefaultValue() != "" && e.optional()
+}
+
+// GoValueType is the golang type returned by the accessor of the element.
+func (e *Element) GoValueType() string {
+	return strings.TrimPrefix(e.GoFieldType(), "*")
+}
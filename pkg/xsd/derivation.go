@@ -0,0 +1,163 @@
+package xsd
+
+import (
+	"sort"
+)
+
+// IsDerivationBase reports whether other named complex types extend this type. Values of such type may be
+// replaced in XML documents by values of derived types, as denoted by xsi:type attribute.
+func (ct *ComplexType) IsDerivationBase() bool {
+	return len(ct.derivations) != 0
+}
+
+// IsDerived reports whether this type extends another named complex type.
+func (ct *ComplexType) IsDerived() bool {
+	return ct.derivedFrom != nil
+}
+
+// Go interface implemented by this type and all the types derived from it.
+func (ct *ComplexType) GoDerivationInterface() string {
+	return ct.GoName() + "Derivation"
+}
+
+// Go type wrapping the derivation interface, which marshals xsi:type attribute of the types derived from this type.
+func (ct *ComplexType) GoDerivationField() string {
+	return ct.GoDerivationInterface() + "Field"
+}
+
+// Go registry of this type and all the types derived from it.
+func (ct *ComplexType) GoDerivationRegistry() string {
+	return ct.GoDerivationInterface() + "s"
+}
+
+// DerivationBases lists all the types this type may stand in for, including the type itself when it is extended.
+func (ct *ComplexType) DerivationBases() []*ComplexType {
+	bases := []*ComplexType{}
+	if ct.IsDerivationBase() {
+		bases = append(bases, ct)
+	}
+	for base := ct.derivedFrom; base != nil; base = base.derivedFrom {
+		bases = append(bases, base)
+	}
+	return bases
+}
+
+// Types derived from this type that are defined in the same go package, including this type itself.
+func (ct *ComplexType) LocalDerivations() []*ComplexType {
+	res := []*ComplexType{}
+	for _, derived := range ct.derivationMembers() {
+		if derived.schema.TargetNamespace == ct.schema.TargetNamespace {
+			res = append(res, derived)
+		}
+	}
+	return res
+}
+
+func (ct *ComplexType) derivationMembers() []*ComplexType {
+	res := []*ComplexType{ct}
+	for _, derived := range ct.derivations {
+		res = append(res, derived.derivationMembers()...)
+	}
+	return res
+}
+
+// Namespace of top-level complex type.
+func (ct *ComplexType) Namespace() string {
+	return ct.schema.TargetNamespace
+}
+
+// ContainsDerivations reports whether the golang type needs custom (un)marshalling of xsi:type polymorphic elements.
+func (e *Element) ContainsDerivations() bool {
+	return containsDerivations(e.Elements())
+}
+
+func (ct *ComplexType) ContainsDerivations() bool {
+	return containsDerivations(ct.Elements())
+}
+
+func containsDerivations(elements []Element) bool {
+	for idx := range elements {
+		if elements[idx].DerivationBase() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// DerivationBase returns the type of this element, if documents may replace it by derived types using xsi:type.
+func (e *Element) DerivationBase() *ComplexType {
+	if e.wildcard != nil || e.ReferencesSubstitutionGroup() {
+		return nil
+	}
+	if e.Type == "" && e.refElm != nil {
+		return e.refElm.DerivationBase()
+	}
+	if ct, ok := e.typ.(*ComplexType); ok && e.Type != "" && ct.IsDerivationBase() {
+		return ct
+	}
+	return nil
+}
+
+// ForeignDerivation pairs a complex type with its base type defined in another go package.
+type ForeignDerivation struct {
+	Base    *ComplexType
+	Derived *ComplexType
+}
+
+func (fd *ForeignDerivation) GoBasePackage() string {
+	return fd.Base.schema.GoPackageName()
+}
+
+// ForeignDerivations lists complex types of this schema that extend types defined elsewhere. Such types need to
+// be registered with the base type's package at runtime.
+func (sch *Schema) ForeignDerivations() []ForeignDerivation {
+	res := []ForeignDerivation{}
+	for idx := range sch.ComplexTypes {
+		ct := &sch.ComplexTypes[idx]
+		for _, base := range ct.DerivationBases() {
+			if base.schema.TargetNamespace != sch.TargetNamespace {
+				res = append(res, ForeignDerivation{Base: base, Derived: ct})
+			}
+		}
+	}
+	return res
+}
+
+func (ws *Workspace) compileDerivations() {
+	for _, schema := range ws.Cache {
+		for idx := range schema.ComplexTypes {
+			// Extending type compiles its base type again within its own schema, restore the defining schema
+			schema.ComplexTypes[idx].schema = schema
+		}
+	}
+	for _, schema := range ws.Cache {
+		for idx := range schema.ComplexTypes {
+			ct := &schema.ComplexTypes[idx]
+			if ct.ComplexContent == nil || ct.ComplexContent.Extension == nil {
+				continue
+			}
+			// Resolve the base again, as types brought in by xsd:include were compiled within the included schema
+			typ, err := schema.findReferencedType(ct.ComplexContent.Extension.Base)
+			base, ok := typ.(*ComplexType)
+			if err != nil || !ok || ct.superseded || base.superseded {
+				// Redefinition extending its original definition is not polymorphic, both have the same name
+				continue
+			}
+			ct.derivedFrom = base
+			base.derivations = append(base.derivations, ct)
+		}
+	}
+	for _, schema := range ws.Cache {
+		for idx := range schema.ComplexTypes {
+			ct := &schema.ComplexTypes[idx]
+			sort.Slice(ct.derivations, func(i, j int) bool {
+				return ct.derivations[i].Name < ct.derivations[j].Name
+			})
+			for _, base := range ct.DerivationBases() {
+				if base.schema.TargetNamespace != schema.TargetNamespace {
+					schema.registerImportedModule(base.schema)
+				}
+			}
+		}
+	}
+}
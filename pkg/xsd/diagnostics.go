@@ -0,0 +1,112 @@
+package xsd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Severity tells whether the diagnosed problem prevents code generation.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic reports a problem found in the schema.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Path     string   `json:"path,omitempty"` // path of the component within the schema, as in CompileError
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	location := d.File
+	if d.Line != 0 {
+		location = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	res := string(d.Severity) + ": "
+	if location != "" {
+		res = location + ": " + res
+	}
+	if d.Path != "" {
+		res += d.Path + ": "
+	}
+	return res + d.Message
+}
+
+// Diagnostics collects the problems found in all the schemas of the workspace, so these can be reported at once.
+type Diagnostics struct {
+	entries []Diagnostic
+	errs    []error
+}
+
+// Entries returns the diagnostics ordered by their location.
+func (d *Diagnostics) Entries() []Diagnostic {
+	res := append([]Diagnostic{}, d.entries...)
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].File != res[j].File {
+			return res[i].File < res[j].File
+		}
+		if res[i].Line != res[j].Line {
+			return res[i].Line < res[j].Line
+		}
+		return res[i].Column < res[j].Column
+	})
+	return res
+}
+
+// HasErrors reports whether any of the problems prevents code generation.
+func (d *Diagnostics) HasErrors() bool {
+	return len(d.errs) != 0
+}
+
+// Err joins the errors collected, *CompileError locates the ones found while compiling the schema.
+func (d *Diagnostics) Err() error {
+	return errors.Join(d.errs...)
+}
+
+func (d *Diagnostics) addError(err error) {
+	diag := Diagnostic{Severity: SeverityError, Message: err.Error()}
+	var ce *CompileError
+	if errors.As(err, &ce) {
+		diag = Diagnostic{
+			Severity: SeverityError, File: ce.File, Line: ce.Line, Column: ce.Column, Path: ce.Path,
+			Message: ce.Err.Error(),
+		}
+	}
+	if d.add(diag) {
+		d.errs = append(d.errs, err)
+	}
+}
+
+func (d *Diagnostics) addWarning(file, format string, args ...any) {
+	d.add(Diagnostic{Severity: SeverityWarning, File: file, Message: fmt.Sprintf(format, args...)})
+}
+
+// add records the diagnostic unless it was reported already. The same component may fail to compile repeatedly,
+// when referenced from multiple places.
+func (d *Diagnostics) add(diag Diagnostic) bool {
+	for _, entry := range d.entries {
+		if entry == diag {
+			return false
+		}
+	}
+	d.entries = append(d.entries, diag)
+	return true
+}
+
+// reportError records the error of top-level component and lets the compilation go on with the next one.
+func (sch *Schema) reportError(err error) {
+	for _, leaf := range leafErrors(err) {
+		sch.diagnostics.addError(sch.compileError(leaf))
+	}
+}
+
+func (sch *Schema) warn(format string, args ...any) {
+	sch.diagnostics.addWarning(sch.filePath, format, args...)
+}
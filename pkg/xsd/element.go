@@ -10,21 +10,33 @@ import (
 
 // Element defines single XML element.
 type Element struct {
-	XMLName         xml.Name `xml:"http://www.w3.org/2001/XMLSchema element"`
-	Name            string   `xml:"name,attr"`
-	nameOverride    string
-	XmlNameOverride string      `xml:"-"`
-	FieldOverride   bool        `xml:"-"`
-	Type            reference   `xml:"type,attr"`
-	Ref             reference   `xml:"ref,attr"`
-	MinOccurs       string      `xml:"minOccurs,attr"`
-	MaxOccurs       string      `xml:"maxOccurs,attr"`
-	Annotation      *Annotation `xml:"annotation"`
-	refElm          *Element
-	ComplexType     *ComplexType `xml:"complexType"`
-	SimpleType      *SimpleType  `xml:"simpleType"`
-	schema          *Schema
-	typ             Type
+	XMLName           xml.Name `xml:"http://www.w3.org/2001/XMLSchema element"`
+	Name              string   `xml:"name,attr"`
+	nameOverride      string
+	XmlNameOverride   string      `xml:"-"`
+	FieldOverride     bool        `xml:"-"`
+	Type              reference   `xml:"type,attr"`
+	Ref               reference   `xml:"ref,attr"`
+	SubstitutionGroup string      `xml:"substitutionGroup,attr"`
+	Abstract          bool        `xml:"abstract,attr"`
+	Nillable          bool        `xml:"nillable,attr"`
+	Default           string      `xml:"default,attr"`
+	Fixed             string      `xml:"fixed,attr"`
+	MinOccurs         string      `xml:"minOccurs,attr"`
+	MaxOccurs         string      `xml:"maxOccurs,attr"`
+	Form              string      `xml:"form,attr"`
+	Annotation        *Annotation `xml:"annotation"`
+	refElm            *Element
+	ComplexType       *ComplexType         `xml:"complexType"`
+	SimpleType        *SimpleType          `xml:"simpleType"`
+	Keys              []IdentityConstraint `xml:"key"`
+	KeyRefs           []IdentityConstraint `xml:"keyref"`
+	Uniques           []IdentityConstraint `xml:"unique"`
+	schema            *Schema
+	typ               Type
+	wildcard          *Any
+	substitutionHeads []*Element
+	substitutes       []*Element
 }
 
 func (e *Element) Attributes() []Attribute {
@@ -39,6 +51,9 @@ func (e *Element) ContainsDocumentation() bool {
 }
 
 func (e *Element) Documentation() string {
+	if e.wildcard != nil && (e.Annotation == nil || len(e.Annotation.Documentations) == 0) {
+		return wildcardDocumentation("Elements", e.wildcard.Namespace, e.wildcard.ProcessContents)
+	}
 	if e.Annotation == nil {
 		return ""
 	}
@@ -56,6 +71,9 @@ func (e *Element) Elements() []Element {
 }
 
 func (e *Element) GoFieldName() string {
+	if e.wildcard != nil {
+		return "Any"
+	}
 	name := e.Name
 	if name == "" {
 		return e.refElm.GoName()
@@ -73,10 +91,25 @@ func (e *Element) GoName() string {
 	return e.GoFieldName()
 }
 
+func (e *Element) GoFieldType() string {
+	if e.IsNillable() {
+		return e.GoMemLayout() + "xsdtypes.Nillable[" + e.GoForeignModule() + e.GoTypeName() + "]"
+	}
+	return e.GoMemLayout() + e.GoForeignModule() + e.GoTypeName()
+}
+
 func (e *Element) GoMemLayout() string {
 	if e.isArray() {
 		return "[]"
 	}
+	if e.IsNillable() && e.optional() {
+		// Absent element is told apart from the one marked by xsi:nil
+		return "*"
+	}
+	if e.ReferencesSubstitutionGroup() || e.DerivationBase() != nil {
+		// Members of substitution group and types derived by extension are represented by interface
+		return ""
+	}
 	if (e.MaxOccurs == "1" || e.MaxOccurs == "") && e.MinOccurs == "0" && e.GoTypeName() != "string" {
 		return "*"
 	}
@@ -84,8 +117,16 @@ func (e *Element) GoMemLayout() string {
 }
 
 func (e *Element) GoTypeName() string {
+	if e.wildcard != nil {
+		return "AnyElement"
+	}
 	if e.Type != "" {
+		if base := e.DerivationBase(); base != nil {
+			return base.GoDerivationInterface()
+		}
 		return e.typ.GoName()
+	} else if e.ReferencesSubstitutionGroup() {
+		return e.refElm.GoSubstituteInterface()
 	} else if e.Ref != "" {
 		return e.refElm.GoTypeName()
 	} else if e.isPlainString() {
@@ -95,17 +136,14 @@ func (e *Element) GoTypeName() string {
 }
 
 func (e *Element) GoForeignModule() string {
+	if e.wildcard != nil {
+		return "xsdtypes."
+	}
 	if e.isPlainString() && e.refElm == nil && e.typ == nil {
 		return ""
 	}
 
-	foreignSchema := (*Schema)(nil)
-	if e.refElm != nil {
-		foreignSchema = e.refElm.schema
-	} else if e.typ != nil {
-		foreignSchema = e.typ.Schema()
-	}
-
+	foreignSchema := e.foreignSchema()
 	if foreignSchema != nil && foreignSchema != e.schema &&
 		foreignSchema.TargetNamespace != e.schema.TargetNamespace {
 		return foreignSchema.GoPackageName() + "."
@@ -113,7 +151,55 @@ func (e *Element) GoForeignModule() string {
 	return ""
 }
 
+// Schema that defines the type of this element.
+func (e *Element) foreignSchema() *Schema {
+	if e.refElm != nil {
+		return e.refElm.schema
+	} else if e.typ != nil {
+		return e.typ.Schema()
+	}
+	return nil
+}
+
+// IsNillable reports whether the element may be marked by xsi:nil attribute, in which case the golang field
+// wraps its type by xsdtypes.Nillable. Polymorphic elements represented by interfaces are not wrapped.
+func (e *Element) IsNillable() bool {
+	if e.wildcard != nil || e.ReferencesSubstitutionGroup() || e.DerivationBase() != nil {
+		return false
+	}
+	if e.Ref != "" && e.refElm != nil {
+		return e.refElm.Nillable
+	}
+	return e.Nillable
+}
+
+// DefaultValue returns the value this element takes when absent, as given either by default= or by fixed=.
+// Repeated and polymorphic elements are not given any values.
+func (e *Element) DefaultValue() string {
+	if e.wildcard != nil || e.isArray() || e.ReferencesSubstitutionGroup() || e.DerivationBase() != nil {
+		return ""
+	}
+	if e.Default == "" && e.Fixed == "" && e.refElm != nil {
+		return e.refElm.DefaultValue()
+	}
+	if e.Fixed != "" {
+		return e.Fixed
+	}
+	return e.Default
+}
+
+// IsFixed reports whether the element must not take any other value than its DefaultValue.
+func (e *Element) IsFixed() bool {
+	if e.Default == "" && e.Fixed == "" && e.refElm != nil {
+		return e.refElm.IsFixed()
+	}
+	return e.Fixed != ""
+}
+
 func (e *Element) Modifiers() string {
+	if e.wildcard != nil {
+		return ""
+	}
 	res := ""
 	if e.optional() {
 		res += ",omitempty"
@@ -121,11 +207,25 @@ func (e *Element) Modifiers() string {
 	return res
 }
 
+// HasNilField reports whether the golang type of top-level element tells apart the element marked by xsi:nil,
+// so that it may be decoded and marshalled as document root.
+func (e *Element) HasNilField() bool {
+	return e.Nillable
+}
+
+// HasNilField is false for complex types, as these are nillable only by the elements declared with them.
+func (ct *ComplexType) HasNilField() bool {
+	return false
+}
+
 func (e *Element) optional() bool {
 	return e.MinOccurs == "0"
 }
 
 func (e *Element) XmlName() string {
+	if e.wildcard != nil {
+		return ",any"
+	}
 	if e.XmlNameOverride != "" {
 		return e.XmlNameOverride
 	}
@@ -136,15 +236,54 @@ func (e *Element) XmlName() string {
 	return name
 }
 
+// XmlElementName is the name of this element in XML documents.
+func (e *Element) XmlElementName() string {
+	if e.Name == "" && e.refElm != nil {
+		return e.refElm.XmlElementName()
+	}
+	return e.Name
+}
+
+// XmlNamespace returns namespace of the child element declared locally or by reference, empty if it is
+// unqualified.
+func (e *Element) XmlNamespace() string {
+	if e.Name == "" && e.refElm != nil {
+		// Global elements are always qualified
+		return e.refElm.schema.targetNamespace()
+	}
+	if e.schema == nil {
+		return ""
+	}
+	form := e.Form
+	if form == "" {
+		form = e.schema.ElementFormDefault
+	}
+	if form != "qualified" {
+		return ""
+	}
+	return e.schema.targetNamespace()
+}
+
 func (e *Element) ContainsText() bool {
 	return e.typ != nil && e.typ.ContainsText()
 }
 
+// GoTextType returns golang type of the text content of the element.
+func (e *Element) GoTextType() string {
+	if ct, ok := e.typ.(*ComplexType); ok {
+		return ct.GoTextType()
+	}
+	return "string"
+}
+
 func (e *Element) isPlainString() bool {
 	return e.SimpleType != nil || (e.Type == "" && e.Ref == "" && e.ComplexType == nil) || (e.typ != nil && e.typ.GoTypeName() == "string")
 }
 
 func (e *Element) isArray() bool {
+	if e.wildcard != nil {
+		return true
+	}
 	if e.MaxOccurs == "unbounded" {
 		return true
 	}
@@ -152,37 +291,44 @@ func (e *Element) isArray() bool {
 	return err == nil && occurs > 1
 }
 
-func (e *Element) compile(s *Schema, parentElement *Element) {
+func (e *Element) compile(s *Schema, parentElement *Element) error {
 	e.schema = s
 	if e.ComplexType != nil {
 		e.typ = e.ComplexType
 		if e.SimpleType != nil {
-			panic("Not implemented: xsd:element " + e.Name + " defines ./xsd:simpleType and ./xsd:complexType together")
+			return fmt.Errorf("%w: xsd:element defines ./xsd:simpleType and ./xsd:complexType together", ErrNotImplemented)
 		} else if e.Type != "" {
-			panic("Not implemented: xsd:element " + e.Name + " defines ./@type= and ./xsd:complexType together")
+			return fmt.Errorf("%w: xsd:element defines ./@type= and ./xsd:complexType together", ErrNotImplemented)
+		}
+		if err := e.typ.compile(s, e); err != nil {
+			return within("complexType", err)
 		}
-		e.typ.compile(s, e)
 	} else if e.SimpleType != nil {
 		e.typ = e.SimpleType
 		if e.Type != "" {
-			panic("Not implemented: xsd:element " + e.Name + " defines ./@type= and ./xsd:simpleType together")
+			return fmt.Errorf("%w: xsd:element defines ./@type= and ./xsd:simpleType together", ErrNotImplemented)
+		}
+		if err := e.typ.compile(s, e); err != nil {
+			return within("simpleType", err)
 		}
-		e.typ.compile(s, e)
 	} else if e.Type != "" {
-		e.typ = e.schema.findReferencedType(e.Type)
-		if e.typ == nil {
-			panic("Cannot resolve type reference: " + string(e.Type))
+		typ, err := e.schema.findReferencedType(e.Type)
+		if err != nil {
+			return err
 		}
+		e.typ = typ
 	} else if e.Ref != "" {
-		e.refElm = e.schema.findReferencedElement(e.Ref)
-		if e.refElm == nil {
-			panic("Cannot resolve element reference: " + e.Ref)
+		refElm, err := e.schema.findReferencedElement(e.Ref)
+		if err != nil {
+			return err
 		}
+		e.refElm = refElm
 	}
 
 	if e.Ref == "" && e.Type == "" && !e.isPlainString() {
-		e.schema.registerInlinedElement(e, parentElement)
+		return e.schema.registerInlinedElement(e, parentElement)
 	}
+	return nil
 }
 
 func (e *Element) prefixNameWithParent(parentElement *Element) {
@@ -0,0 +1,238 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	// ErrNotImplemented is wrapped by errors of XSD constructs this tool does not support.
+	ErrNotImplemented = errors.New("not implemented")
+	// ErrUnresolved is wrapped by errors of references to unknown schema components.
+	ErrUnresolved = errors.New("cannot resolve")
+)
+
+// CompileError locates the schema component that could not be compiled.
+type CompileError struct {
+	File   string
+	Line   int // line of the component within the file, zero if unknown
+	Column int
+	Path   string // path of the component within the schema, e.g. complexType[@name=Foo]/sequence/element[2]
+	Err    error
+}
+
+func (e *CompileError) Error() string {
+	location := e.File
+	if e.Line != 0 {
+		location = fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	}
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %v", location, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", location, e.Path, e.Err)
+}
+
+func (e *CompileError) Unwrap() error {
+	return e.Err
+}
+
+// componentError carries path of the failing component, which is completed while the error propagates up to the
+// top-level component of the schema.
+type componentError struct {
+	steps  []string
+	schema *Schema // schema defining the top-level component, once the path is complete
+	err    error
+}
+
+func (e *componentError) Error() string {
+	return strings.Join(e.steps, "/") + ": " + e.err.Error()
+}
+
+func (e *componentError) Unwrap() error {
+	return e.err
+}
+
+// within prefixes path of the failing component with the step leading to it from its parent component.
+func within(step string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errs, ok := joinedErrors(err); ok {
+		for idx := range errs {
+			errs[idx] = within(step, errs[idx])
+		}
+		return errors.Join(errs...)
+	}
+	ce, ok := err.(*componentError)
+	if !ok {
+		return &componentError{steps: []string{step}, err: err}
+	}
+	if ce.schema == nil {
+		ce.steps = append([]string{step}, ce.steps...)
+	}
+	return ce
+}
+
+// withinTopLevel completes path of the failing component with the top-level component of the given schema.
+func withinTopLevel(sch *Schema, step string, err error) error {
+	err = within(step, err)
+	for _, leaf := range leafErrors(err) {
+		if ce, ok := leaf.(*componentError); ok && ce.schema == nil {
+			ce.schema = sch
+		}
+	}
+	return err
+}
+
+// joinedErrors returns errors of distinct components joined by errors.Join. Compilation goes on after component
+// fails, so that all the failing components are reported at once.
+func joinedErrors(err error) ([]error, bool) {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil, false
+	}
+	return append([]error{}, joined.Unwrap()...), true
+}
+
+func leafErrors(err error) []error {
+	errs, ok := joinedErrors(err)
+	if !ok {
+		return []error{err}
+	}
+	res := []error{}
+	for _, e := range errs {
+		res = append(res, leafErrors(e)...)
+	}
+	return res
+}
+
+// indexedStep returns path step to the idx-th child of the given kind.
+func indexedStep(kind string, idx int) string {
+	return fmt.Sprintf("%s[%d]", kind, idx+1)
+}
+
+// namedStep returns path step to the top-level component of the given kind.
+func namedStep(kind, name string) string {
+	return fmt.Sprintf("%s[@name=%s]", kind, name)
+}
+
+// typeStep returns path step to the top-level type definition.
+func typeStep(typ Type) string {
+	switch t := typ.(type) {
+	case *ComplexType:
+		return namedStep("complexType", t.Name)
+	case *SimpleType:
+		return namedStep("simpleType", t.Name)
+	case *AttributeGroup:
+		return namedStep("attributeGroup", t.Name)
+	}
+	return ""
+}
+
+// compileError converts error of schema compilation to CompileError locating the failing component.
+func (sch *Schema) compileError(err error) error {
+	ce, ok := err.(*componentError)
+	if !ok {
+		return &CompileError{File: sch.filePath, Err: err}
+	}
+	owner := ce.schema
+	if owner == nil {
+		owner = sch
+	}
+	res := &CompileError{File: owner.filePath, Path: strings.Join(ce.steps, "/"), Err: ce.err}
+	if pos, found := lookupPosition(owner.positions, ce.steps); found {
+		res.File, res.Line, res.Column = pos.file, pos.line, pos.column
+	}
+	return res
+}
+
+// position of the component within its XSD file.
+type position struct {
+	file         string
+	line, column int
+}
+
+// lookupPosition returns position of the component, or of its closest ancestor known.
+func lookupPosition(positions map[string]position, steps []string) (position, bool) {
+	normalized := make([]string, len(steps))
+	for idx, step := range steps {
+		if !strings.Contains(step, "[") {
+			step += "[1]"
+		}
+		normalized[idx] = step
+	}
+	for length := len(normalized); length > 0; length-- {
+		if pos, found := positions[strings.Join(normalized[:length], "/")]; found {
+			return pos, true
+		}
+	}
+	return position{}, false
+}
+
+// scanPositions records position of each XSD element by its path. Top-level components (and the ones given
+// by xsd:redefine) are identified by their names, nested ones by their index among siblings of the same kind.
+// The schema element starts at the offset given, schemas embedded in WSDL need not be the root element.
+func scanPositions(file string, r io.Reader, offset int64) map[string]position {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = charset.NewReaderLabel
+	type frame struct {
+		path     string
+		topLevel bool
+		counts   map[string]int
+	}
+	positions := map[string]position{}
+	stack := []frame{}
+	for d.InputOffset() < offset {
+		if _, err := d.RawToken(); err != nil {
+			return positions
+		}
+	}
+	for {
+		line, column := d.InputPos()
+		token, err := d.RawToken()
+		if err != nil {
+			return positions
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				stack = append(stack, frame{topLevel: true, counts: map[string]int{}})
+				continue
+			}
+			parent := &stack[len(stack)-1]
+			parent.counts[t.Name.Local]++
+			step := indexedStep(t.Name.Local, parent.counts[t.Name.Local]-1)
+			if parent.topLevel {
+				for _, attr := range t.Attr {
+					if attr.Name.Space == "" && attr.Name.Local == "name" {
+						step = namedStep(t.Name.Local, attr.Value)
+					}
+				}
+			}
+			path := step
+			if parent.path != "" {
+				path = parent.path + "/" + step
+			}
+			redefinition := parent.topLevel && (t.Name.Local == "redefine" || t.Name.Local == "override")
+			if redefinition {
+				// Redefined components are compiled as top-level ones
+				path = ""
+			} else if _, found := positions[path]; !found {
+				positions[path] = position{file: file, line: line, column: column}
+			}
+			stack = append(stack, frame{path: path, topLevel: redefinition, counts: map[string]int{}})
+		case xml.EndElement:
+			if len(stack) != 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				return positions
+			}
+		}
+	}
+}
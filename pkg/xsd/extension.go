@@ -2,6 +2,9 @@ package xsd
 
 import (
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 type Extension struct {
@@ -9,22 +12,26 @@ type Extension struct {
 	Base             reference        `xml:"base,attr"`
 	AttributesDirect []Attribute      `xml:"attribute"`
 	AttributeGroups  []AttributeGroup `xml:"attributeGroup"`
+	AnyAttribute     *AnyAttribute    `xml:"anyAttribute"`
 	Sequence         *Sequence        `xml:"sequence"`
+	Group            *Group           `xml:"group"`
 	typ              Type
 }
 
 func (ext *Extension) Attributes() []Attribute {
-	attrs := ext.AttributesDirect
+	attrs := ext.AttributesDirect[:len(ext.AttributesDirect):len(ext.AttributesDirect)]
 	if ext.typ != nil {
 		attrs = append(attrs, ext.typ.Attributes()...)
-		attrs = deduplicateAttributes(attrs)
 	}
 	for idx := range ext.AttributeGroups {
 		attrGroup := ext.AttributeGroups[idx]
 		attrs = append(attrs, attrGroup.Attributes()...)
-		attrs = deduplicateAttributes(attrs)
 	}
-	return attrs
+	if ext.AnyAttribute != nil {
+		attrs = append(attrs, ext.AnyAttribute.attribute())
+	}
+	// Attribute wildcard of the extension is union of the wildcards of the base type and of the extension
+	return deduplicateAttributes(deduplicateAttributeWildcards(attrs))
 }
 
 func (ext *Extension) Elements() []Element {
@@ -32,6 +39,9 @@ func (ext *Extension) Elements() []Element {
 	if ext.Sequence != nil {
 		elements = append(elements, ext.Sequence.Elements()...)
 	}
+	if ext.Group != nil {
+		elements = append(elements, ext.Group.Elements()...)
+	}
 	if ext.typ != nil {
 		elements = append(elements, ext.typ.Elements()...)
 		elements = deduplicateElements(elements)
@@ -86,37 +96,74 @@ func (ext *Extension) ContainsText() bool {
 	return ext.Base == "xsd:string" || (ext.typ != nil && ext.typ.ContainsText())
 }
 
-func (ext *Extension) compile(sch *Schema, parentElement *Element) {
+// GoTextType returns golang type of the text content. Types of xsdtypes package are kept, so that the text is
+// decoded, the other types are represented by string.
+func (ext *Extension) GoTextType() string {
+	switch typ := ext.typ.(type) {
+	case staticType:
+		if strings.HasPrefix(string(typ), "xsdtypes.") {
+			return string(typ)
+		}
+	case *SimpleType:
+		if typ.WrapsRuntimeType() {
+			return typ.GoTypeName()
+		}
+	case *ComplexType:
+		return typ.GoTextType()
+	}
+	return "string"
+}
+
+func (ext *Extension) compile(sch *Schema, parentElement *Element) error {
+	var errs []error
+	if ext.AnyAttribute != nil {
+		ext.AnyAttribute.compile(sch)
+	}
 	if ext.Sequence != nil {
-		ext.Sequence.compile(sch, parentElement)
+		if err := ext.Sequence.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("sequence", err))
+		}
+	}
+	if ext.Group != nil {
+		if err := ext.Group.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("group", err))
+		}
 	}
 	if ext.Base == "" {
-		panic("Not implemented: xsd:extension/@base empty, cannot extend unknown type")
+		return fmt.Errorf("%w: xsd:extension/@base empty, cannot extend unknown type", ErrNotImplemented)
 	}
 
-	ext.typ = sch.findReferencedType(ext.Base)
-	if ext.typ == nil {
-		panic("Cannot build xsd:extension: unknown type: " + string(ext.Base))
+	typ, err := sch.compileReferencedType(ext.Base, parentElement)
+	if err != nil {
+		errs = append(errs, err)
 	}
-	ext.typ.compile(sch, parentElement)
+	ext.typ = typ
 
 	for idx := range ext.AttributeGroups {
 		attrGroup := &ext.AttributeGroups[idx]
-		attrGroup.compile(sch, parentElement)
+		if err := attrGroup.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("attributeGroup", idx), err))
+		}
 	}
 
 	// Handle improbable name clash. Consider XSD defining two attributes on the element:
 	// "id" and "Id", this would create name clash given the camelization we do.
 	goNames := map[string]uint{}
-	for idx := range ext.Attributes() {
-		attribute := &ext.Attributes()[idx]
-		attribute.compile(sch)
+	for idx := range ext.AttributesDirect {
+		attribute := &ext.AttributesDirect[idx]
+		if err := attribute.compile(sch); err != nil {
+			errs = append(errs, within(indexedStep("attribute", idx), err))
+		}
 
 		count := goNames[attribute.GoName()]
 		count += 1
 		goNames[attribute.GoName()] = count
 		attribute.DuplicateCount = count
+		if count > 1 {
+			sch.warn("attribute '%s' clashes with another attribute once camelized, generated as %s", attribute.Name, attribute.GoName())
+		}
 		// Second GoName may be different depending on the DuplicateCount
 		goNames[attribute.GoName()] = count
 	}
+	return errors.Join(errs...)
 }
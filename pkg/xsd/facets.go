@@ -0,0 +1,162 @@
+package xsd
+
+// Facet constrains the value space of simple type (xsd:length, xsd:pattern, ...).
+type Facet struct {
+	Value string `xml:"value,attr"`
+	Fixed bool   `xml:"fixed,attr"`
+}
+
+// Facets of simple type, including the ones inherited from its base types. Patterns hold golang regular
+// expressions, all of them have to match. Other facets are kept in their XSD lexical form.
+type Facets struct {
+	Patterns       []string
+	Enumeration    []string
+	Length         string
+	MinLength      string
+	MaxLength      string
+	MinInclusive   string
+	MaxInclusive   string
+	MinExclusive   string
+	MaxExclusive   string
+	TotalDigits    string
+	FractionDigits string
+	WhiteSpace     string
+}
+
+// Built-in string types that collapse whitespace of their values.
+var collapsedStringTypes = map[string]bool{
+	"token": true, "language": true, "Name": true, "NCName": true, "NMTOKEN": true, "ID": true, "IDREF": true,
+	"ENTITY": true, "anyURI": true, "QName": true, "NOTATION": true,
+}
+
+// Facets returns constraints of this simple type, or nil when the type does not restrict its base type.
+func (st *SimpleType) Facets() *Facets {
+	f := st.facets()
+	if len(f.Patterns) == 0 && len(f.Enumeration) == 0 && f.Length == "" && f.MinLength == "" &&
+		f.MaxLength == "" && f.MinInclusive == "" && f.MaxInclusive == "" && f.MinExclusive == "" &&
+		f.MaxExclusive == "" && f.TotalDigits == "" && f.FractionDigits == "" && f.WhiteSpace == "" {
+		return nil
+	}
+	return &f
+}
+
+func (st *SimpleType) facets() Facets {
+	f := Facets{}
+	r := st.Restriction
+	if r == nil {
+		if u := st.union(); u != nil && u.enumerationOnly() {
+			for _, enum := range u.Enums() {
+				f.Enumeration = append(f.Enumeration, enum.Value)
+			}
+		}
+		return f
+	}
+
+	switch base := r.typ.(type) {
+	case *SimpleType:
+		f = base.facets()
+	case staticType:
+		if base == "string" && r.Base.Name() == "normalizedString" {
+			f.WhiteSpace = "replace"
+		} else if base == "string" && collapsedStringTypes[r.Base.Name()] {
+			f.WhiteSpace = "collapse"
+		}
+	}
+
+	if r.pattern != "" {
+		f.Patterns = append(f.Patterns[:len(f.Patterns):len(f.Patterns)], r.pattern)
+	}
+	if len(r.EnumsDirect) != 0 {
+		f.Enumeration = nil
+		for _, enum := range r.EnumsDirect {
+			f.Enumeration = append(f.Enumeration, enum.Value)
+		}
+	}
+	for _, facet := range []struct {
+		value *string
+		facet *Facet
+	}{
+		{&f.Length, r.Length},
+		{&f.MinLength, r.MinLength},
+		{&f.MaxLength, r.MaxLength},
+		{&f.MinInclusive, r.MinInclusive},
+		{&f.MaxInclusive, r.MaxInclusive},
+		{&f.MinExclusive, r.MinExclusive},
+		{&f.MaxExclusive, r.MaxExclusive},
+		{&f.TotalDigits, r.TotalDigits},
+		{&f.FractionDigits, r.FractionDigits},
+		{&f.WhiteSpace, r.WhiteSpace},
+	} {
+		if facet.facet != nil {
+			*facet.value = facet.facet.Value
+		}
+	}
+	if f.WhiteSpace == "preserve" {
+		f.WhiteSpace = ""
+	}
+	return f
+}
+
+// HasConstraints reports whether the value of this attribute may violate constraints of its type.
+func (a *Attribute) HasConstraints() bool {
+	if a.wildcard != nil {
+		return a.WildcardNamespace() != ""
+	}
+	if a.typ == nil {
+		return false
+	}
+	_, static := a.typ.(staticType)
+	return !static
+}
+
+// IsOptional reports whether the attribute may be absent, which the golang field denotes by its zero value.
+func (a *Attribute) IsOptional() bool {
+	return a.optional()
+}
+
+// IsOptional reports whether the element may be absent, which the golang field denotes by its zero value.
+func (e *Element) IsOptional() bool {
+	return e.optional()
+}
+
+// HasConstraints reports whether the value of this element may violate constraints of its type.
+func (e *Element) HasConstraints() bool {
+	if e.wildcard != nil {
+		return e.WildcardNamespace() != ""
+	}
+	if e.ReferencesSubstitutionGroup() || e.DerivationBase() != nil {
+		return true
+	}
+	if e.Type == "" && e.refElm != nil {
+		return e.refElm.HasConstraints()
+	}
+	if e.Type == "" && e.isPlainString() {
+		// Anonymous simple types are represented by their underlying golang type
+		return false
+	}
+	_, static := e.typ.(staticType)
+	return e.typ != nil && !static
+}
+
+// ContainsConstraints reports whether attributes or child elements of the golang type need to be validated.
+func (e *Element) ContainsConstraints() bool {
+	return containsConstraints(e.Attributes(), e.Elements())
+}
+
+func (ct *ComplexType) ContainsConstraints() bool {
+	return containsConstraints(ct.Attributes(), ct.Elements())
+}
+
+func containsConstraints(attributes []Attribute, elements []Element) bool {
+	for idx := range attributes {
+		if attributes[idx].HasConstraints() {
+			return true
+		}
+	}
+	for idx := range elements {
+		if elements[idx].HasConstraints() {
+			return true
+		}
+	}
+	return false
+}
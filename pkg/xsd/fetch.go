@@ -0,0 +1,169 @@
+package xsd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultFetchTimeout limits download of a single schema by HTTPFetcher without Timeout.
+	DefaultFetchTimeout = time.Minute
+	// DefaultFetchMaxSize limits size of a single schema downloaded by HTTPFetcher without MaxSize.
+	DefaultFetchMaxSize = 32 << 20
+)
+
+var (
+	// ErrRemoteNotAllowed is wrapped by errors of schemas given by URL, when the workspace has no Fetcher.
+	ErrRemoteNotAllowed = errors.New("loading of remote schemas is not allowed")
+	// ErrCacheMiss is wrapped by errors of schemas missing in the cache of offline HTTPFetcher.
+	ErrCacheMiss = errors.New("schema is not cached")
+	// ErrSchemaTooLarge is wrapped by errors of schemas exceeding the size limit of HTTPFetcher.
+	ErrSchemaTooLarge = errors.New("schema is too large")
+)
+
+// Fetcher downloads schemas given by http:// or https:// URL.
+type Fetcher interface {
+	Fetch(url string) ([]byte, error)
+}
+
+// HTTPFetcher downloads schemas over HTTP. Downloaded schemas are kept in content-addressed cache: each URL refers
+// to the digest of the document it was downloaded to, so the subsequent runs are reproducible and need no network.
+type HTTPFetcher struct {
+	Client   *http.Client  // http.DefaultClient if nil
+	Timeout  time.Duration // limits download of a single schema, DefaultFetchTimeout if zero
+	MaxSize  int64         // limits size of a single schema in bytes, DefaultFetchMaxSize if zero
+	CacheDir string        // nothing is cached if empty
+	Offline  bool          // schemas missing in the cache are not downloaded, but reported by ErrCacheMiss
+}
+
+func (f *HTTPFetcher) Fetch(url string) ([]byte, error) {
+	if f.CacheDir != "" {
+		data, err := f.cached(url)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if f.Offline {
+		return nil, fmt.Errorf("%w: %s", ErrCacheMiss, url)
+	}
+
+	data, err := f.download(url)
+	if err != nil {
+		return nil, err
+	}
+	if f.CacheDir != "" {
+		if err := f.store(url, data); err != nil {
+			return nil, fmt.Errorf("could not cache %s: %w", url, err)
+		}
+	}
+	return data, nil
+}
+
+func (f *HTTPFetcher) download(url string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	timeout := f.Timeout
+	if timeout == 0 {
+		timeout = DefaultFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download %s: %s", url, resp.Status)
+	}
+	maxSize := f.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultFetchMaxSize
+	}
+	// One byte over the limit tells the schema exceeding it apart from the one of the exact size
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrSchemaTooLarge, url, maxSize)
+	}
+	return data, nil
+}
+
+// Cache directory holds the documents named by their digest in sha256/ and the digests named by digest of their
+// URL in urls/.
+func (f *HTTPFetcher) urlFile(url string) string {
+	return filepath.Join(f.CacheDir, "urls", digest([]byte(url)))
+}
+
+func (f *HTTPFetcher) documentFile(sum string) string {
+	return filepath.Join(f.CacheDir, "sha256", sum)
+}
+
+func (f *HTTPFetcher) cached(url string) ([]byte, error) {
+	sum, err := os.ReadFile(f.urlFile(url))
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(f.documentFile(strings.TrimSpace(string(sum))))
+	if err != nil {
+		return nil, err
+	}
+	if digest(data) != strings.TrimSpace(string(sum)) {
+		return nil, fmt.Errorf("cached copy of %s is corrupted, digest does not match", url)
+	}
+	return data, nil
+}
+
+func (f *HTTPFetcher) store(url string, data []byte) error {
+	sum := digest(data)
+	if err := writeFileAtomic(f.documentFile(sum), data); err != nil {
+		return err
+	}
+	return writeFileAtomic(f.urlFile(url), []byte(sum+"\n"))
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFileAtomic prevents concurrent runs from reading partially written cache entries.
+func writeFileAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,78 @@
+package xsd
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// osFS opens files of the local filesystem. Unlike os.DirFS, it accepts the OS-specific paths, absolute ones included.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(filepath.Clean(name))
+}
+
+// dir returns directory of the schema file, schemaLocation of the documents it brings in is relative to it.
+func (ws *Workspace) dir(xsdPath string) string {
+	if isRemote(xsdPath) {
+		return xsdPath[:strings.LastIndex(xsdPath, "/")+1]
+	}
+	if _, local := ws.fsys.(osFS); local {
+		return filepath.Dir(xsdPath)
+	}
+	return path.Dir(xsdPath)
+}
+
+// resolveLocation returns path of the document given by schemaLocation within the workspace filesystem.
+func (ws *Workspace) resolveLocation(baseDir, schemaLocation string) string {
+	if isRemote(schemaLocation) {
+		return schemaLocation
+	}
+	if isRemote(baseDir) {
+		base, err := url.Parse(baseDir)
+		if err != nil {
+			return baseDir + schemaLocation
+		}
+		ref, err := url.Parse(schemaLocation)
+		if err != nil {
+			return baseDir + schemaLocation
+		}
+		return base.ResolveReference(ref).String()
+	}
+	if _, local := ws.fsys.(osFS); local {
+		return filepath.Join(baseDir, schemaLocation)
+	}
+	return path.Join(baseDir, schemaLocation)
+}
+
+func isRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// readSchema reads the schema from the workspace filesystem, or downloads it if given by URL.
+func (ws *Workspace) readSchema(xsdPath string) (*Schema, error) {
+	if !isRemote(xsdPath) {
+		return ReadSchemaFromFS(ws.fsys, xsdPath)
+	}
+	data, err := ws.readFile(xsdPath)
+	if err != nil {
+		return nil, err
+	}
+	return readSchema(xsdPath, data)
+}
+
+// readFile reads the document from the workspace filesystem, or downloads it if given by URL.
+func (ws *Workspace) readFile(file string) ([]byte, error) {
+	if !isRemote(file) {
+		return fs.ReadFile(ws.fsys, file)
+	}
+	if ws.fetcher == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRemoteNotAllowed, file)
+	}
+	return ws.fetcher.Fetch(file)
+}
@@ -0,0 +1,89 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// Group defines named model group (xsd:group), or a reference to such a group.
+type Group struct {
+	XMLName     xml.Name     `xml:"http://www.w3.org/2001/XMLSchema group"`
+	Name        string       `xml:"name,attr"`
+	Ref         reference    `xml:"ref,attr"`
+	MinOccurs   string       `xml:"minOccurs,attr"`
+	MaxOccurs   string       `xml:"maxOccurs,attr"`
+	Annotation  *Annotation  `xml:"annotation"`
+	Sequence    *Sequence    `xml:"sequence"`
+	SequenceAll *SequenceAll `xml:"all"`
+	Choice      *Choice      `xml:"choice"`
+	refGroup    *Group
+	schema      *Schema
+	allElements []Element
+	compiled    bool
+}
+
+func (g *Group) Elements() []Element {
+	return g.allElements
+}
+
+func (g *Group) compile(sch *Schema, parentElement *Element) error {
+	if g.Ref != "" {
+		g.schema = sch
+		refGroup, err := sch.findReferencedGroup(g.Ref)
+		if err != nil {
+			return err
+		}
+		g.refGroup = refGroup
+
+		// Flatten particles of the referenced group, while propagating cardinality of the reference downwards
+		g.allElements = []Element{}
+		for _, el := range g.refGroup.Elements() {
+			if g.MinOccurs == "0" {
+				el.MinOccurs = "0"
+			}
+			if g.isArray() {
+				el.MaxOccurs = "unbounded"
+			}
+			el.schema = sch
+			if foreignSchema := el.foreignSchema(); foreignSchema != nil && foreignSchema != sch &&
+				foreignSchema.TargetNamespace != sch.TargetNamespace {
+				sch.registerImportedModule(foreignSchema)
+			}
+			g.allElements = append(g.allElements, el)
+		}
+		return nil
+	}
+
+	// Top-level group definitions may be referenced multiple times, but are compiled only once
+	if g.compiled {
+		return nil
+	}
+	g.compiled = true
+	g.schema = sch
+
+	if g.Sequence != nil {
+		if err := g.Sequence.compile(sch, parentElement); err != nil {
+			return within("sequence", err)
+		}
+		g.allElements = g.Sequence.Elements()
+	} else if g.SequenceAll != nil {
+		if err := g.SequenceAll.compile(sch, parentElement); err != nil {
+			return within("all", err)
+		}
+		g.allElements = g.SequenceAll.Elements()
+	} else if g.Choice != nil {
+		if err := g.Choice.compile(sch, parentElement); err != nil {
+			return within("choice", err)
+		}
+		g.allElements = g.Choice.Elements()
+	}
+	return nil
+}
+
+func (g *Group) isArray() bool {
+	if g.MaxOccurs == "unbounded" {
+		return true
+	}
+	occurs, err := strconv.Atoi(g.MaxOccurs)
+	return err == nil && occurs > 1
+}
@@ -0,0 +1,124 @@
+package xsd
+
+import (
+	"encoding/xml"
+)
+
+// IdentityConstraint is xsd:key, xsd:keyref or xsd:unique declared within xsd:element. Its selector and fields
+// are given by the restricted XPath subset of XSD, these are evaluated at runtime by the xsdtypes package.
+type IdentityConstraint struct {
+	XMLName  xml.Name
+	Name     string    `xml:"name,attr"`
+	Refer    reference `xml:"refer,attr"`
+	Selector XPath     `xml:"selector"`
+	Fields   []XPath   `xml:"field"`
+	element  *Element
+}
+
+type XPath struct {
+	XPath string `xml:"xpath,attr"`
+}
+
+// Kind returns key, keyref or unique.
+func (ic *IdentityConstraint) Kind() string {
+	return ic.XMLName.Local
+}
+
+// ReferName returns name of the key or unique referenced by keyref.
+func (ic *IdentityConstraint) ReferName() string {
+	return ic.Refer.Name()
+}
+
+// ElementName returns name of the element declaring the constraint, each of its occurrences is the scope
+// of the constraint.
+func (ic *IdentityConstraint) ElementName() string {
+	return ic.element.Name
+}
+
+func (ic *IdentityConstraint) FieldXPaths() []string {
+	res := make([]string, len(ic.Fields))
+	for idx := range ic.Fields {
+		res[idx] = ic.Fields[idx].XPath
+	}
+	return res
+}
+
+// IdentityConstraints returns all the identity constraints declared by elements of this schema. Keys and
+// uniques precede keyrefs, so that referenced constraints are known first.
+func (sch *Schema) IdentityConstraints() []*IdentityConstraint {
+	keys := []*IdentityConstraint{}
+	keyRefs := []*IdentityConstraint{}
+	visited := map[*ComplexType]bool{}
+	var collect func(el *Element)
+	collect = func(el *Element) {
+		for _, list := range [][]IdentityConstraint{el.Keys, el.Uniques} {
+			for idx := range list {
+				ic := list[idx]
+				ic.element = el
+				keys = append(keys, &ic)
+			}
+		}
+		for idx := range el.KeyRefs {
+			ic := el.KeyRefs[idx]
+			ic.element = el
+			keyRefs = append(keyRefs, &ic)
+		}
+		// Local elements of anonymous types are not reachable otherwise
+		if el.Ref == "" && el.ComplexType != nil && !visited[el.ComplexType] {
+			visited[el.ComplexType] = true
+			children := el.Elements()
+			for idx := range children {
+				collect(&children[idx])
+			}
+		}
+	}
+	for idx := range sch.Elements {
+		collect(&sch.Elements[idx])
+	}
+	for idx := range sch.ComplexTypes {
+		children := sch.ComplexTypes[idx].Elements()
+		for cidx := range children {
+			collect(&children[cidx])
+		}
+	}
+	return deduplicateIdentityConstraints(append(keys, keyRefs...))
+}
+
+// AnyElements returns names of the lone child elements, which golang fields are tagged xml:",any", by golang type
+// and field name. Identity constraints need these to select the elements by name.
+func (sch *Schema) AnyElements() map[string]string {
+	res := map[string]string{}
+	add := func(goType string, children []Element) {
+		for idx := range children {
+			child := &children[idx]
+			if child.wildcard != nil || child.XmlName() != ",any" {
+				continue
+			}
+			name := child.Name
+			if name == "" && child.refElm != nil {
+				name = child.refElm.Name
+			}
+			res[goType+"."+child.GoFieldName()] = name
+		}
+	}
+	for _, el := range sch.ExportableElements() {
+		add(el.GoName(), el.Elements())
+	}
+	for _, ct := range sch.ExportableComplexTypes() {
+		add(ct.GoName(), ct.Elements())
+	}
+	return res
+}
+
+func deduplicateIdentityConstraints(constraints []*IdentityConstraint) []*IdentityConstraint {
+	seen := map[string]bool{}
+	res := []*IdentityConstraint{}
+	for _, ic := range constraints {
+		if seen[ic.Name] {
+			continue
+		}
+		seen[ic.Name] = true
+		res = append(res, ic)
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// List defines simple type which values are whitespace separated lists of items (xsd:list).
+type List struct {
+	XMLName    xml.Name    `xml:"http://www.w3.org/2001/XMLSchema list"`
+	ItemType   reference   `xml:"itemType,attr"`
+	SimpleType *SimpleType `xml:"simpleType"`
+	schema     *Schema
+	typ        Type
+}
+
+// Built-in XSD list types and their item types.
+var builtinListTypes = map[string]string{
+	"ENTITIES": "ENTITY",
+	"IDREFS":   "IDREF",
+	"NMTOKENS": "NMTOKEN",
+}
+
+func isBuiltinListType(name string) bool {
+	_, found := builtinListTypes[name]
+	return found
+}
+
+func (l *List) GoItemTypeName() string {
+	return goTypeReference(l.typ, l.schema)
+}
+
+func (l *List) compile(sch *Schema, parentElement *Element) error {
+	l.schema = sch
+	if l.typ != nil {
+		// Item type of built-in list types is known upfront
+		return nil
+	}
+	if l.SimpleType != nil {
+		if l.ItemType != "" {
+			return fmt.Errorf("%w: xsd:list defines ./@itemType and ./xsd:simpleType together", ErrNotImplemented)
+		}
+		l.typ = l.SimpleType
+		return within("simpleType", l.typ.compile(sch, parentElement))
+	} else if l.ItemType != "" {
+		typ, err := sch.findReferencedType(l.ItemType)
+		if err != nil {
+			return err
+		}
+		l.typ = typ
+		return nil
+	}
+	return fmt.Errorf("%w: xsd:list defines neither ./@itemType nor ./xsd:simpleType", ErrNotImplemented)
+}
@@ -0,0 +1,379 @@
+package xsd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// XSD regular expressions (https://www.w3.org/TR/xmlschema-2/#regexs) differ from the golang ones: they are
+// implicitly anchored, ^ and $ are plain characters, \d, \w and . have different meaning, there are \i and \c
+// escapes for XML names and character classes may be subtracted from each other.
+//
+// translatePatterns rewrites XSD regular expressions given by the same restriction to the equivalent golang
+// one. Value has to match any of the patterns.
+func translatePatterns(patterns []string) (string, error) {
+	bodies := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		body, err := translatePattern(pattern)
+		if err != nil {
+			return "", fmt.Errorf("%w; in pattern '%s'", err, pattern)
+		}
+		bodies = append(bodies, body)
+	}
+	res := "^(?:" + strings.Join(bodies, "|") + ")$"
+	if _, err := regexp.Compile(res); err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+func translatePattern(pattern string) (string, error) {
+	p := patternTranslator{input: []rune(pattern)}
+	var out strings.Builder
+	for !p.eof() {
+		r := p.next()
+		switch r {
+		case '\\':
+			text, _, err := p.escape(false)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(text)
+		case '[':
+			text, err := p.class()
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(text)
+		case '.':
+			out.WriteString(`[^\n\r]`)
+		case '^', '$':
+			out.WriteString(`\` + string(r))
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String(), nil
+}
+
+type patternTranslator struct {
+	input []rune
+	pos   int
+}
+
+func (p *patternTranslator) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *patternTranslator) next() rune {
+	r := p.input[p.pos]
+	p.pos++
+	return r
+}
+
+func (p *patternTranslator) peek(offset int) rune {
+	if p.pos+offset >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos+offset]
+}
+
+// escape translates escape sequence following the backslash. It returns golang text of the escape usable
+// at the given place, together with the set of characters it stands for.
+func (p *patternTranslator) escape(inClass bool) (string, runeSet, error) {
+	if p.eof() {
+		return "", nil, fmt.Errorf("pattern ends with backslash")
+	}
+	r := p.next()
+	switch r {
+	case 'n':
+		return `\n`, runeSet{{'\n', '\n'}}, nil
+	case 'r':
+		return `\r`, runeSet{{'\r', '\r'}}, nil
+	case 't':
+		return `\t`, runeSet{{'\t', '\t'}}, nil
+	case '\\', '|', '.', '-', '^', '?', '*', '+', '{', '}', '(', ')', '[', ']', '$':
+		return `\` + string(r), runeSet{{r, r}}, nil
+	case 'p', 'P':
+		name, set, err := p.category()
+		if err != nil {
+			return "", nil, err
+		}
+		if name == "" {
+			// Unicode blocks are not known to golang, these are given by explicit ranges
+			if r == 'P' {
+				set = set.negate()
+				if inClass {
+					return "", set, nil
+				}
+			}
+			if inClass {
+				return set.String(), set, nil
+			}
+			return "[" + set.String() + "]", set, nil
+		}
+		if r == 'P' {
+			return `\P{` + name + `}`, set.negate(), nil
+		}
+		return `\p{` + name + `}`, set, nil
+	}
+
+	escape, found := multiCharEscapes[r]
+	if !found {
+		return "", nil, fmt.Errorf("unknown escape \\%c", r)
+	}
+	if inClass {
+		// Empty text denotes escape that cannot be part of golang character class
+		return escape.inClass, escape.set, nil
+	}
+	return escape.text, escape.set, nil
+}
+
+// category parses name of \p escape. It returns golang name of the character category together with the set
+// of characters, name is empty for Unicode blocks (\p{IsX}) unknown to golang.
+func (p *patternTranslator) category() (string, runeSet, error) {
+	if p.eof() || p.next() != '{' {
+		return "", nil, fmt.Errorf("malformed \\p escape")
+	}
+	start := p.pos
+	for !p.eof() && p.peek(0) != '}' {
+		p.pos++
+	}
+	if p.eof() {
+		return "", nil, fmt.Errorf("malformed \\p escape")
+	}
+	name := string(p.input[start:p.pos])
+	p.pos++
+	if table, found := unicode.Categories[name]; found {
+		return name, runeSetFromTable(table), nil
+	}
+	if strings.HasPrefix(name, "Is") {
+		if set, found := unicodeBlocks[strings.TrimPrefix(name, "Is")]; found {
+			return "", set, nil
+		}
+		return "", nil, fmt.Errorf("unknown unicode block \\p{%s}", name)
+	}
+	return "", nil, fmt.Errorf("unsupported character category \\p{%s}", name)
+}
+
+// class translates character class expression following the opening bracket.
+func (p *patternTranslator) class() (string, error) {
+	set, simple, text, err := p.classBody()
+	if err != nil {
+		return "", err
+	}
+	if simple {
+		return text, nil
+	}
+	return "[" + set.String() + "]", nil
+}
+
+// classBody parses character class up to and including closing bracket. Classes without subtraction and
+// negated escapes are kept in their textual form, others are expanded to the explicit character ranges.
+func (p *patternTranslator) classBody() (runeSet, bool, string, error) {
+	negated := false
+	if p.peek(0) == '^' {
+		negated = true
+		p.pos++
+	}
+	var set runeSet
+	var text strings.Builder
+	simple := true
+	first := true
+	for {
+		if p.eof() {
+			return nil, false, "", fmt.Errorf("unterminated character class")
+		}
+		r := p.next()
+		switch {
+		case r == ']' && !first:
+			if negated {
+				return set.negate(), simple, "[^" + text.String() + "]", nil
+			}
+			return set.normalize(), simple, "[" + text.String() + "]", nil
+		case r == '-' && p.peek(0) == '[':
+			// Subtraction, must be the last item of the class
+			p.pos++
+			subtracted, _, _, err := p.classBody()
+			if err != nil {
+				return nil, false, "", err
+			}
+			if p.eof() || p.next() != ']' {
+				return nil, false, "", fmt.Errorf("character class subtraction must be the last item of class")
+			}
+			if negated {
+				set = set.negate()
+			}
+			return set.normalize().subtract(subtracted), false, "", nil
+		default:
+			lo, loText, loSet, err := p.classChar(r)
+			if err != nil {
+				return nil, false, "", err
+			}
+			if loSet != nil {
+				set = append(set, loSet...)
+				text.WriteString(loText)
+				if loText == "" {
+					simple = false
+				}
+				break
+			}
+			if p.peek(0) == '-' && p.peek(1) != ']' && p.peek(1) != '[' && p.peek(1) != 0 {
+				p.pos++
+				hi, hiText, hiSet, err := p.classChar(p.next())
+				if err != nil {
+					return nil, false, "", err
+				}
+				if hiSet != nil || hi < lo {
+					return nil, false, "", fmt.Errorf("invalid character range")
+				}
+				set = append(set, runeRange{lo, hi})
+				text.WriteString(loText + "-" + hiText)
+				break
+			}
+			set = append(set, runeRange{lo, lo})
+			text.WriteString(loText)
+		}
+		first = false
+	}
+}
+
+// classChar translates single item of character class. Escapes standing for multiple characters are returned
+// as a set, while single characters are returned as rune.
+func (p *patternTranslator) classChar(r rune) (rune, string, runeSet, error) {
+	if r != '\\' {
+		return r, regexp.QuoteMeta(string(r)), nil, nil
+	}
+	text, set, err := p.escape(true)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	if len(set) == 1 && set[0].lo == set[0].hi && !strings.HasPrefix(text, `\p`) {
+		return set[0].lo, text, nil, nil
+	}
+	return 0, text, set, nil
+}
+
+type runeRange struct {
+	lo, hi rune
+}
+
+// runeSet is sorted list of non-overlapping character ranges.
+type runeSet []runeRange
+
+func runeSetFromTable(table *unicode.RangeTable) runeSet {
+	set := runeSet{}
+	add := func(lo, hi, stride rune) {
+		if stride == 1 {
+			set = append(set, runeRange{lo, hi})
+			return
+		}
+		for c := lo; c <= hi; c += stride {
+			set = append(set, runeRange{c, c})
+		}
+	}
+	for _, r := range table.R16 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range table.R32 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	return set.normalize()
+}
+
+func (s runeSet) normalize() runeSet {
+	sorted := append(runeSet{}, s...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].lo < sorted[j].lo })
+	res := runeSet{}
+	for _, r := range sorted {
+		if len(res) != 0 && r.lo <= res[len(res)-1].hi+1 {
+			if r.hi > res[len(res)-1].hi {
+				res[len(res)-1].hi = r.hi
+			}
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+func (s runeSet) negate() runeSet {
+	res := runeSet{}
+	next := rune(0)
+	for _, r := range s.normalize() {
+		if r.lo > next {
+			res = append(res, runeRange{next, r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= unicode.MaxRune {
+		res = append(res, runeRange{next, unicode.MaxRune})
+	}
+	return res
+}
+
+func (s runeSet) subtract(other runeSet) runeSet {
+	negated := other.negate()
+	res := runeSet{}
+	for _, a := range s {
+		for _, b := range negated {
+			lo, hi := max(a.lo, b.lo), min(a.hi, b.hi)
+			if lo <= hi {
+				res = append(res, runeRange{lo, hi})
+			}
+		}
+	}
+	return res.normalize()
+}
+
+// String returns contents of golang character class matching the set.
+func (s runeSet) String() string {
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteString(quoteClassRune(r.lo))
+		if r.hi != r.lo {
+			b.WriteString("-" + quoteClassRune(r.hi))
+		}
+	}
+	return b.String()
+}
+
+func quoteClassRune(r rune) string {
+	if r < 0x80 && unicode.IsPrint(r) && !strings.ContainsRune(`\-[]^`, r) {
+		return string(r)
+	}
+	return fmt.Sprintf(`\x{%X}`, r)
+}
+
+var (
+	// XML name characters as given by https://www.w3.org/TR/xml/#NT-NameStartChar
+	nameStartChars = runeSet{{':', ':'}, {'A', 'Z'}, {'_', '_'}, {'a', 'z'}, {0xC0, 0xD6}, {0xD8, 0xF6},
+		{0xF8, 0x2FF}, {0x370, 0x37D}, {0x37F, 0x1FFF}, {0x200C, 0x200D}, {0x2070, 0x218F}, {0x2C00, 0x2FEF},
+		{0x3001, 0xD7FF}, {0xF900, 0xFDCF}, {0xFDF0, 0xFFFD}, {0x10000, 0xEFFFF}}
+	nameChars = append(runeSet{{'-', '.'}, {'0', '9'}, {0xB7, 0xB7}, {0x300, 0x36F}, {0x203F, 0x2040}},
+		nameStartChars...).normalize()
+	spaceChars   = runeSet{{'\t', '\n'}, {'\r', '\r'}, {' ', ' '}}
+	digitChars   = runeSetFromTable(unicode.Nd)
+	nonWordChars = append(append(runeSetFromTable(unicode.P), runeSetFromTable(unicode.Z)...),
+		runeSetFromTable(unicode.C)...).normalize()
+
+	multiCharEscapes = map[rune]struct {
+		text    string
+		inClass string
+		set     runeSet
+	}{
+		's': {`[\t\n\r ]`, `\t\n\r `, spaceChars},
+		'S': {`[^\t\n\r ]`, "", spaceChars.negate()},
+		'i': {"[" + nameStartChars.String() + "]", nameStartChars.String(), nameStartChars},
+		'I': {"[^" + nameStartChars.String() + "]", "", nameStartChars.negate()},
+		'c': {"[" + nameChars.String() + "]", nameChars.String(), nameChars},
+		'C': {"[^" + nameChars.String() + "]", "", nameChars.negate()},
+		'd': {`\p{Nd}`, `\p{Nd}`, digitChars},
+		'D': {`\P{Nd}`, `\P{Nd}`, digitChars.negate()},
+		'w': {`[^\p{P}\p{Z}\p{C}]`, "", nonWordChars.negate()},
+		'W': {`[\p{P}\p{Z}\p{C}]`, `\p{P}\p{Z}\p{C}`, nonWordChars},
+	}
+)
@@ -0,0 +1,36 @@
+package xsd
+
+// isQName reports whether the attribute holds xsdtypes.QName, which prefix is resolved against the namespace
+// declarations of the enclosing element.
+func (a *Attribute) isQName() bool {
+	return a.wildcard == nil && a.typ != nil && a.typ.GoTypeName() == "xsdtypes.QName"
+}
+
+// QNameAttributes lists the attributes, which prefixes are resolved by UnmarshalXML and declared by MarshalXML
+// of the golang type.
+func (e *Element) QNameAttributes() []Attribute {
+	return qnameAttributes(e.Attributes())
+}
+
+func (ct *ComplexType) QNameAttributes() []Attribute {
+	return qnameAttributes(ct.Attributes())
+}
+
+// ContainsQNameAttributes reports whether the golang type needs custom (un)marshalling of QName attributes.
+func (e *Element) ContainsQNameAttributes() bool {
+	return len(e.QNameAttributes()) != 0
+}
+
+func (ct *ComplexType) ContainsQNameAttributes() bool {
+	return len(ct.QNameAttributes()) != 0
+}
+
+func qnameAttributes(attributes []Attribute) []Attribute {
+	res := []Attribute{}
+	for idx := range attributes {
+		if attributes[idx].isQName() {
+			res = append(res, attributes[idx])
+		}
+	}
+	return res
+}
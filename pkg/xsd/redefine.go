@@ -0,0 +1,245 @@
+package xsd
+
+import (
+	"encoding/xml"
+)
+
+// Redefine brings in the schema like xsd:include does, replacing the components it defines anew. Complex types,
+// simple types and groups given by xsd:redefine may refer to their original definition by their own name. XSD 1.1
+// xsd:override is handled the same way, except that its components replace the original ones altogether.
+type Redefine struct {
+	XMLName         xml.Name
+	SchemaLocation  string           `xml:"schemaLocation,attr"`
+	Elements        []Element        `xml:"element"`
+	Attributes      []Attribute      `xml:"attribute"`
+	AttributeGroups []AttributeGroup `xml:"attributeGroup"`
+	Groups          []Group          `xml:"group"`
+	ComplexTypes    []ComplexType    `xml:"complexType"`
+	SimpleTypes     []SimpleType     `xml:"simpleType"`
+	RedefinedSchema *Schema          `xml:"-"`
+}
+
+func (r *Redefine) load(ws *Workspace, baseDir string) (err error) {
+	if file := ws.locate(baseDir, r.SchemaLocation, ""); file != "" {
+		r.RedefinedSchema, err = ws.loadXsd(file, true)
+	}
+	return
+}
+
+func (r *Redefine) isOverride() bool {
+	return r.XMLName.Local == "override"
+}
+
+// supersededName is given to the original definition referenced by its redefinition. It is not a valid NCName,
+// thus it cannot clash with any name given by the schema.
+func supersededName(name string) string {
+	return name + " redefined"
+}
+
+// redefinedSchema returns the schema brought in with its components replaced by the redefined ones. The original
+// definitions referenced by the redefined ones are kept under the superseded name, these are not exported.
+func (r *Redefine) redefinedSchema(sch *Schema) *Schema {
+	res := *r.RedefinedSchema
+	isSelf := func(ref reference, name string) bool {
+		return !r.isOverride() && ref.Name() == name && sch.xmlnsByPrefixInternal(ref.NsPrefix()) == sch.TargetNamespace
+	}
+	superseded := func(ref reference) reference {
+		if ref.NsPrefix() == "" {
+			return reference(supersededName(ref.Name()))
+		}
+		return reference(ref.NsPrefix() + ":" + supersededName(ref.Name()))
+	}
+
+	res.ComplexTypes = []ComplexType{}
+	for _, ct := range r.RedefinedSchema.ComplexTypes {
+		if idx := findComplexType(r.ComplexTypes, ct.Name); idx != -1 {
+			redefined := &r.ComplexTypes[idx]
+			if base := redefined.baseReference(); base != nil && isSelf(*base, ct.Name) {
+				*base = superseded(*base)
+				ct.Name = supersededName(ct.Name)
+				ct.superseded = true
+				res.ComplexTypes = append(res.ComplexTypes, ct)
+			}
+			continue
+		}
+		res.ComplexTypes = append(res.ComplexTypes, ct)
+	}
+	res.ComplexTypes = append(res.ComplexTypes, r.ComplexTypes...)
+
+	res.SimpleTypes = []SimpleType{}
+	for _, st := range r.RedefinedSchema.SimpleTypes {
+		if idx := findSimpleType(r.SimpleTypes, st.Name); idx != -1 {
+			redefined := &r.SimpleTypes[idx]
+			if redefined.Restriction != nil && isSelf(redefined.Restriction.Base, st.Name) {
+				redefined.Restriction.Base = superseded(redefined.Restriction.Base)
+				st.Name = supersededName(st.Name)
+				st.superseded = true
+				res.SimpleTypes = append(res.SimpleTypes, st)
+			}
+			continue
+		}
+		res.SimpleTypes = append(res.SimpleTypes, st)
+	}
+	res.SimpleTypes = append(res.SimpleTypes, r.SimpleTypes...)
+
+	res.Groups = []Group{}
+	for _, grp := range r.RedefinedSchema.Groups {
+		if idx := findGroup(r.Groups, grp.Name); idx != -1 {
+			referenced := false
+			for _, ref := range r.Groups[idx].groupReferences() {
+				if isSelf(*ref, grp.Name) {
+					*ref = superseded(*ref)
+					referenced = true
+				}
+			}
+			if referenced {
+				grp.Name = supersededName(grp.Name)
+				res.Groups = append(res.Groups, grp)
+			}
+			continue
+		}
+		res.Groups = append(res.Groups, grp)
+	}
+	res.Groups = append(res.Groups, r.Groups...)
+
+	// Attribute groups do not refer to other attribute groups within this tool, their redefinitions replace them
+	res.AttributeGroups = []AttributeGroup{}
+	for _, att := range r.RedefinedSchema.AttributeGroups {
+		if findAttributeGroup(r.AttributeGroups, att.Name) == -1 {
+			res.AttributeGroups = append(res.AttributeGroups, att)
+		}
+	}
+	res.AttributeGroups = append(res.AttributeGroups, r.AttributeGroups...)
+
+	res.Elements = []Element{}
+	for _, el := range r.RedefinedSchema.Elements {
+		if findElement(r.Elements, el.Name) == -1 {
+			res.Elements = append(res.Elements, el)
+		}
+	}
+	res.Elements = append(res.Elements, r.Elements...)
+
+	res.Attributes = []Attribute{}
+	for _, attr := range r.RedefinedSchema.Attributes {
+		if findAttribute(r.Attributes, attr.Name) == -1 {
+			res.Attributes = append(res.Attributes, attr)
+		}
+	}
+	res.Attributes = append(res.Attributes, r.Attributes...)
+	return &res
+}
+
+// baseReference returns base of the complex content or simple content derivation.
+func (ct *ComplexType) baseReference() *reference {
+	if cc := ct.ComplexContent; cc != nil {
+		if cc.Extension != nil {
+			return &cc.Extension.Base
+		}
+		if cc.Restriction != nil {
+			return &cc.Restriction.Base
+		}
+	}
+	if sc := ct.SimpleContent; sc != nil {
+		if sc.Extension != nil {
+			return &sc.Extension.Base
+		}
+		if sc.Restriction != nil {
+			return &sc.Restriction.Base
+		}
+	}
+	return nil
+}
+
+// groupReferences returns references to other groups made by the content of this group.
+func (g *Group) groupReferences() []*reference {
+	res := []*reference{}
+	var fromSequence func(s *Sequence)
+	var fromChoice func(c *Choice)
+	fromGroups := func(groups []Group) {
+		for idx := range groups {
+			if groups[idx].Ref != "" {
+				res = append(res, &groups[idx].Ref)
+			}
+		}
+	}
+	fromSequence = func(s *Sequence) {
+		if s == nil {
+			return
+		}
+		fromGroups(s.Groups)
+		for idx := range s.Choices {
+			fromChoice(&s.Choices[idx])
+		}
+	}
+	fromChoice = func(c *Choice) {
+		if c == nil {
+			return
+		}
+		fromGroups(c.Groups)
+		for idx := range c.Sequences {
+			fromSequence(&c.Sequences[idx])
+		}
+	}
+	fromSequence(g.Sequence)
+	fromChoice(g.Choice)
+	if g.SequenceAll != nil {
+		for idx := range g.SequenceAll.Choices {
+			fromChoice(&g.SequenceAll.Choices[idx])
+		}
+	}
+	return res
+}
+
+func findComplexType(types []ComplexType, name string) int {
+	for idx := range types {
+		if types[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func findSimpleType(types []SimpleType, name string) int {
+	for idx := range types {
+		if types[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func findGroup(groups []Group, name string) int {
+	for idx := range groups {
+		if groups[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func findAttributeGroup(groups []AttributeGroup, name string) int {
+	for idx := range groups {
+		if groups[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func findElement(elements []Element, name string) int {
+	for idx := range elements {
+		if elements[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func findAttribute(attributes []Attribute, name string) int {
+	for idx := range attributes {
+		if attributes[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
@@ -2,6 +2,8 @@ package xsd
 
 import (
 	"encoding/xml"
+	"errors"
+	"fmt"
 )
 
 type Restriction struct {
@@ -9,41 +11,73 @@ type Restriction struct {
 	Base             reference      `xml:"base,attr"`
 	AttributesDirect []Attribute    `xml:"attribute"`
 	EnumsDirect      []Enumeration  `xml:"enumeration"`
+	Patterns         []Facet        `xml:"pattern"`
+	Length           *Facet         `xml:"length"`
+	MinLength        *Facet         `xml:"minLength"`
+	MaxLength        *Facet         `xml:"maxLength"`
+	MinInclusive     *Facet         `xml:"minInclusive"`
+	MaxInclusive     *Facet         `xml:"maxInclusive"`
+	MinExclusive     *Facet         `xml:"minExclusive"`
+	MaxExclusive     *Facet         `xml:"maxExclusive"`
+	TotalDigits      *Facet         `xml:"totalDigits"`
+	FractionDigits   *Facet         `xml:"fractionDigits"`
+	WhiteSpace       *Facet         `xml:"whiteSpace"`
 	SimpleContent    *SimpleContent `xml:"simpleContent"`
 	schema           *Schema
 	typ              Type
+	pattern          string
+	patternDone      bool
 }
 
-func (r *Restriction) compile(sch *Schema, parentElement *Element) {
+func (r *Restriction) compile(sch *Schema, parentElement *Element) error {
 	r.schema = sch
+	var errs []error
 	for idx := range r.AttributesDirect {
 		attribute := &r.AttributesDirect[idx]
-		attribute.compile(sch)
+		if err := attribute.compile(sch); err != nil {
+			errs = append(errs, within(indexedStep("attribute", idx), err))
+		}
 	}
 	if r.SimpleContent != nil {
-		r.SimpleContent.compile(sch, parentElement)
+		if err := r.SimpleContent.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("simpleContent", err))
+		}
 	}
 
 	if r.Base == "" {
-		panic("Not implemented: xsd:restriction/@base empty, cannot extend unknown type")
+		return fmt.Errorf("%w: xsd:restriction/@base empty, cannot restrict unknown type", ErrNotImplemented)
 	}
 
-	r.typ = sch.findReferencedType(r.Base)
-	if r.typ == nil {
-		panic("Cannot build xsd:extension: unknown type: " + string(r.Base))
+	typ, err := sch.compileReferencedType(r.Base, parentElement)
+	if err != nil {
+		errs = append(errs, err)
 	}
-	r.typ.compile(sch, parentElement)
+	r.typ = typ
+
+	if len(r.Patterns) != 0 && !r.patternDone {
+		r.patternDone = true
+		patterns := make([]string, len(r.Patterns))
+		for idx := range r.Patterns {
+			patterns[idx] = r.Patterns[idx].Value
+		}
+		r.pattern, err = translatePatterns(patterns)
+		if err != nil {
+			sch.warn("cannot translate xsd:pattern restricting %s, values will not be validated against it: %v", r.Base, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (r *Restriction) Attributes() []Attribute {
-	result := make([]Attribute, 0)
+	// Attributes declared by the restriction take precedence over the inherited ones (e.g. to fix their values)
+	result := append([]Attribute{}, r.AttributesDirect...)
 	if r.typ != nil {
 		result = append(result, r.typ.Attributes()...)
 	}
 	if r.SimpleContent != nil {
 		result = append(result, r.SimpleContent.Attributes()...)
 	}
-	result = deduplicateAttributes(append(result, r.AttributesDirect...))
+	result = deduplicateAttributes(result)
 
 	return injectSchemaIntoAttributes(r.schema, result)
 }
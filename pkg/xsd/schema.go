@@ -1,10 +1,11 @@
 package xsd
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -12,49 +13,63 @@ import (
 	"golang.org/x/net/html/charset"
 )
 
+// Go package providing runtime support for generated code.
+const xsdtypesPackage = "github.com/gocomply/xsd2go/pkg/xsdtypes"
+
 // Schema is the root XSD element.
 type Schema struct {
 	XMLName               xml.Name         `xml:"http://www.w3.org/2001/XMLSchema schema"`
 	Xmlns                 Xmlns            `xml:"-"`
 	TargetNamespace       string           `xml:"targetNamespace,attr"`
+	ElementFormDefault    string           `xml:"elementFormDefault,attr"`
 	Annotation            *Annotation      `xml:"annotation"`
 	Includes              []Include        `xml:"include"`
 	Imports               []Import         `xml:"import"`
+	Redefines             []Redefine       `xml:"redefine"`
+	Overrides             []Redefine       `xml:"override"`
 	Elements              []Element        `xml:"element"`
 	Attributes            []Attribute      `xml:"attribute"`
 	AttributeGroups       []AttributeGroup `xml:"attributeGroup"`
+	Groups                []Group          `xml:"group"`
 	ComplexTypes          []ComplexType    `xml:"complexType"`
 	SimpleTypes           []SimpleType     `xml:"simpleType"`
 	importedModules       map[string]*Schema
 	ModulesPath           string `xml:"-"`
 	filePath              string
+	positions             map[string]position
+	diagnostics           *Diagnostics
 	inlinedElements       []Element
+	builtinTypes          []*SimpleType
 	goPackageNameOverride string
+	staticTypeOverrides   map[string]staticType
+	noTargetNamespace     bool // TargetNamespace is made up, the schema components are unqualified
 }
 
 func ReadSchemaFromFile(xsdPath string) (*Schema, error) {
-	xsdPathClean := filepath.Clean(xsdPath)
-	f, err := os.Open(xsdPathClean)
+	return ReadSchemaFromFS(osFS{}, xsdPath)
+}
+
+// ReadSchemaFromFS parses the schema file found within the filesystem.
+func ReadSchemaFromFS(fsys fs.FS, xsdPath string) (*Schema, error) {
+	data, err := fs.ReadFile(fsys, xsdPath)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := f.Close()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error while closing file %s, %v", xsdPathClean, err)
-		}
-	}()
+	return readSchema(xsdPath, data)
+}
 
-	schema, err := parseSchema(f)
+func readSchema(xsdPath string, data []byte) (*Schema, error) {
+	schema, err := parseSchema(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("%w; while processing %s", err, xsdPath)
 	}
+	schema.positions = scanPositions(xsdPath, bytes.NewReader(data), 0)
 
 	return schema, nil
 }
 
 func parseSchema(f io.Reader) (*Schema, error) {
-	schema := Schema{importedModules: map[string]*Schema{}}
+	schema := Schema{importedModules: map[string]*Schema{}, diagnostics: &Diagnostics{}}
 	d := xml.NewDecoder(f)
 	d.CharsetReader = charset.NewReaderLabel
 
@@ -75,72 +90,183 @@ func (sch *Schema) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 
 func (sch *Schema) compile() {
 	if sch.TargetNamespace == "" {
-		fmt.Fprintf(os.Stderr, "Warning: missing explicit /xsd:chema/@targetNamespace; using '%s' instead\n", sch.GoPackageName())
+		sch.warn("missing explicit /xsd:schema/@targetNamespace; using '%s' instead", sch.GoPackageName())
 		sch.TargetNamespace = sch.GoPackageName()
+		sch.noTargetNamespace = true
 	}
 
+	for idx := range sch.Groups {
+		grp := &sch.Groups[idx]
+		if err := grp.compile(sch, nil); err != nil {
+			sch.reportError(withinTopLevel(sch, namedStep("group", grp.Name), err))
+		}
+	}
 	for idx := range sch.Elements {
 		el := &sch.Elements[idx]
-		el.compile(sch, nil)
+		if err := el.compile(sch, nil); err != nil {
+			sch.reportError(withinTopLevel(sch, namedStep("element", el.Name), err))
+		}
 	}
 	for idx := range sch.AttributeGroups {
 		att := &sch.AttributeGroups[idx]
-		att.compile(sch, nil)
+		if err := att.compile(sch, nil); err != nil {
+			sch.reportError(withinTopLevel(sch, namedStep("attributeGroup", att.Name), err))
+		}
 	}
 	for idx := range sch.ComplexTypes {
 		ct := &sch.ComplexTypes[idx]
-		ct.compile(sch, nil)
+		if err := ct.compile(sch, nil); err != nil {
+			sch.reportError(withinTopLevel(sch, namedStep("complexType", ct.Name), err))
+		}
 	}
 	for idx := range sch.SimpleTypes {
 		st := &sch.SimpleTypes[idx]
-		st.compile(sch, nil)
+		if err := st.compile(sch, nil); err != nil {
+			sch.reportError(withinTopLevel(sch, namedStep("simpleType", st.Name), err))
+		}
 	}
 }
 
-func (sch *Schema) findReferencedAttribute(ref reference) *Attribute {
-	innerSchema := sch.findReferencedSchemaByPrefix(ref.NsPrefix())
-	if innerSchema == nil {
-		panic("Internal error: referenced attribute '" + ref + "' cannot be found.")
+// include merges components of schema brought in by xsd:include or xsd:redefine into this schema.
+func (sch *Schema) include(isch *Schema) {
+	sch.Imports = append(isch.Imports, sch.Imports...)
+	sch.Elements = append(isch.Elements, sch.Elements...)
+	sch.Attributes = append(isch.Attributes, sch.Attributes...)
+	sch.AttributeGroups = append(isch.AttributeGroups, sch.AttributeGroups...)
+	sch.Groups = append(isch.Groups, sch.Groups...)
+	for gIdx := range sch.Groups {
+		// Group definitions are to be compiled again within the including schema
+		sch.Groups[gIdx].compiled = false
+	}
+	sch.ComplexTypes = append(isch.ComplexTypes, sch.ComplexTypes...)
+	sch.SimpleTypes = append(isch.SimpleTypes, sch.SimpleTypes...)
+	sch.inlinedElements = append(isch.inlinedElements, sch.inlinedElements...)
+	sch.builtinTypes = append(isch.builtinTypes, sch.builtinTypes...)
+	for key, imported := range isch.importedModules {
+		sch.importedModules[key] = imported
+	}
+	for path, pos := range isch.positions {
+		// Components defined by the including schema take precedence, as these do in the merged lists
+		if _, found := sch.positions[path]; !found {
+			sch.positions[path] = pos
+		}
 	}
-	return innerSchema.GetAttribute(ref.Name())
 }
 
-func (sch *Schema) findReferencedElement(ref reference) *Element {
-	innerSchema := sch.findReferencedSchemaByPrefix(ref.NsPrefix())
-	if innerSchema == nil {
-		panic("Internal error: referenced element '" + string(ref) + "' cannot be found.")
+func (sch *Schema) findReferencedAttribute(ref reference) (*Attribute, error) {
+	innerSchema, err := sch.findReferencedSchemaByPrefix(ref.NsPrefix())
+	if err != nil {
+		return nil, err
+	}
+	attr := innerSchema.GetAttribute(ref.Name())
+	if attr == nil {
+		return nil, fmt.Errorf("%w attribute reference '%s'", ErrUnresolved, ref)
+	}
+	return attr, nil
+}
+
+func (sch *Schema) findReferencedElement(ref reference) (*Element, error) {
+	innerSchema, err := sch.findReferencedSchemaByPrefix(ref.NsPrefix())
+	if err != nil {
+		return nil, err
+	}
+	if innerSchema != sch {
+		sch.registerImportedModule(innerSchema)
+	}
+	el := innerSchema.GetElement(ref.Name())
+	if el == nil {
+		return nil, fmt.Errorf("%w element reference '%s'", ErrUnresolved, ref)
+	}
+	return el, nil
+}
+
+func (sch *Schema) findReferencedGroup(ref reference) (*Group, error) {
+	innerSchema, err := sch.findReferencedSchemaByPrefix(ref.NsPrefix())
+	if err != nil {
+		return nil, err
 	}
 	if innerSchema != sch {
 		sch.registerImportedModule(innerSchema)
 	}
-	return innerSchema.GetElement(ref.Name())
+	grp := innerSchema.GetGroup(ref.Name())
+	if grp == nil {
+		return nil, fmt.Errorf("%w group reference '%s'", ErrUnresolved, ref)
+	}
+	// Group definition may be referenced before it was compiled as part of its schema
+	if err := grp.compile(innerSchema, nil); err != nil {
+		return nil, withinTopLevel(innerSchema, namedStep("group", grp.Name), err)
+	}
+	return grp, nil
+}
+
+func (sch *Schema) findReferencedType(ref reference) (Type, error) {
+	typ, _, err := sch.resolveType(ref)
+	return typ, err
 }
 
-func (sch *Schema) findReferencedType(ref reference) Type {
-	innerSchema := sch.findReferencedSchemaByPrefix(ref.NsPrefix())
+// compileReferencedType resolves the type and compiles it. Errors are located at the definition of the type, which
+// is returned even if it failed to compile.
+func (sch *Schema) compileReferencedType(ref reference, parentElement *Element) (Type, error) {
+	typ, innerSchema, err := sch.resolveType(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := typ.compile(sch, parentElement); err != nil {
+		return typ, withinTopLevel(innerSchema, typeStep(typ), err)
+	}
+	return typ, nil
+}
+
+func (sch *Schema) resolveType(ref reference) (Type, *Schema, error) {
+	xmlnsUri, err := sch.xmlnsByPrefix(ref.NsPrefix())
+	if err != nil {
+		return nil, nil, err
+	}
+	innerSchema := sch.findReferencedSchemaByXmlns(xmlnsUri)
 	if innerSchema == nil {
-		xmlnsUri := sch.Xmlns.UriByPrefix(ref.NsPrefix())
 		if xmlnsUri == "http://www.w3.org/2001/XMLSchema" { //nolint:revive
-			return StaticType(ref.Name())
+			if isBuiltinListType(ref.Name()) {
+				return sch.builtinListType(ref.Name()), sch, nil
+			}
+			typ, err := sch.staticType(ref.Name())
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, overridden := sch.staticTypeOverrides[ref.Name()]; lossyStaticTypes[ref.Name()] && !overridden {
+				sch.warn("xsd:%s is generated as %s, which cannot hold all of its values", ref.Name(), typ)
+			}
+			return typ, sch, nil
 		}
-		panic("Internal error: referenced type '" + string(ref) + "' cannot be found.")
+		return nil, nil, fmt.Errorf("%w type '%s', namespace '%s' was not imported", ErrUnresolved, ref, xmlnsUri)
 	}
 	if innerSchema != sch {
 		sch.registerImportedModule(innerSchema)
 	}
-	return innerSchema.GetType(ref.Name())
+	typ := innerSchema.GetType(ref.Name())
+	if typ == nil {
+		return nil, nil, fmt.Errorf("%w type reference '%s'", ErrUnresolved, ref)
+	}
+	return typ, innerSchema, nil
 }
 
-func (sch *Schema) findReferencedSchemaByPrefix(xmlnsPrefix string) *Schema {
-	return sch.findReferencedSchemaByXmlns(sch.xmlnsByPrefix(xmlnsPrefix))
+func (sch *Schema) findReferencedSchemaByPrefix(xmlnsPrefix string) (*Schema, error) {
+	uri, err := sch.xmlnsByPrefix(xmlnsPrefix)
+	if err != nil {
+		return nil, err
+	}
+	innerSchema := sch.findReferencedSchemaByXmlns(uri)
+	if innerSchema == nil {
+		return nil, fmt.Errorf("%w namespace '%s', it was not imported", ErrUnresolved, uri)
+	}
+	return innerSchema, nil
 }
 
-func (sch *Schema) xmlnsByPrefix(xmlnsPrefix string) string {
+func (sch *Schema) xmlnsByPrefix(xmlnsPrefix string) (string, error) {
 	uri := sch.xmlnsByPrefixInternal(xmlnsPrefix)
 	if uri == "" {
-		panic("Internal error: Unknown xmlns prefix: " + xmlnsPrefix)
+		return "", fmt.Errorf("%w xmlns prefix '%s'", ErrUnresolved, xmlnsPrefix)
 	}
-	return uri
+	return uri, nil
 }
 
 func (sch *Schema) xmlnsByPrefixInternal(xmlnsPrefix string) string {
@@ -181,12 +307,29 @@ func (sch *Schema) findReferencedSchemaByXmlns(xmlns string) *Schema {
 	return nil
 }
 
+// targetNamespace returns namespace of the qualified schema components, empty if the schema has no
+// targetNamespace.
+func (sch *Schema) targetNamespace() string {
+	if sch == nil || sch.noTargetNamespace {
+		return ""
+	}
+	return sch.TargetNamespace
+}
+
 func (sch *Schema) Empty() bool {
 	return len(sch.Elements) == 0 && len(sch.ComplexTypes) == 0 && len(sch.ExportableSimpleTypes()) == 0
 }
 
 func (sch *Schema) encodingXmlImportNeeded() bool {
-	return len(sch.Elements) != 0 || len(sch.ComplexTypes) != 0
+	if len(sch.Elements) != 0 || len(sch.ComplexTypes) != 0 {
+		return true
+	}
+	for _, typ := range sch.ExportableSimpleTypes() {
+		if typ.IsUnion() {
+			return true
+		}
+	}
+	return false
 }
 
 func deduplicateElementsLossfree(elements []Element) []Element {
@@ -216,7 +359,7 @@ func (sch *Schema) ExportableComplexTypes() []ComplexType {
 	var res []ComplexType
 	for _, typ := range sch.ComplexTypes {
 		_, found := elCache[typ.GoName()]
-		if !found {
+		if !found && !typ.superseded {
 			res = append(res, typ)
 		}
 	}
@@ -232,10 +375,13 @@ func (sch *Schema) ExportableSimpleTypes() []SimpleType {
 	var res []SimpleType
 	for _, typ := range sch.SimpleTypes {
 		_, found := elCache[typ.GoName()]
-		if !found {
+		if !found && !typ.superseded {
 			res = append(res, typ)
 		}
 	}
+	for _, typ := range sch.builtinTypes {
+		res = append(res, *typ)
+	}
 	return res
 }
 
@@ -257,6 +403,15 @@ func (sch *Schema) GetElement(name string) *Element {
 	return nil
 }
 
+func (sch *Schema) GetGroup(name string) *Group {
+	for idx, grp := range sch.Groups {
+		if grp.Name == name {
+			return &sch.Groups[idx]
+		}
+	}
+	return nil
+}
+
 func (sch *Schema) GetType(name string) Type {
 	for idx, typ := range sch.ComplexTypes {
 		if typ.Name == name {
@@ -273,12 +428,42 @@ func (sch *Schema) GetType(name string) Type {
 			return &sch.AttributeGroups[idx]
 		}
 	}
+	if isBuiltinListType(name) {
+		return sch.builtinListType(name)
+	}
 	if IsStaticType(name) {
-		return StaticType(name)
+		typ, _ := sch.staticType(name)
+		return typ
 	}
 	return nil
 }
 
+// staticType returns golang counterpart of the built-in XSD type.
+func (sch *Schema) staticType(name string) (staticType, error) {
+	if typ, found := sch.staticTypeOverrides[name]; found {
+		return typ, nil
+	}
+	return StaticType(name)
+}
+
+// Built-in list types (xsd:NMTOKENS and friends) are generated as simple types in each schema that uses them.
+func (sch *Schema) builtinListType(name string) *SimpleType {
+	for _, st := range sch.builtinTypes {
+		if st.Name == name {
+			return st
+		}
+	}
+	itemType, _ := StaticType(builtinListTypes[name])
+	st := &SimpleType{
+		Name:   name,
+		List:   &List{typ: itemType},
+		schema: sch,
+	}
+	_ = st.compile(sch, nil) // item type is known upfront, there is nothing to fail
+	sch.builtinTypes = append(sch.builtinTypes, st)
+	return st
+}
+
 func (sch *Schema) ContainsDocumentation() bool {
 	return sch.Documentation() != ""
 }
@@ -301,7 +486,48 @@ func (sch *Schema) GoPackageName() string {
 	if xmlnsPrefix == "" {
 		xmlnsPrefix = strings.TrimSuffix(filepath.Base(sch.filePath), ".xsd")
 	}
-	return strings.ReplaceAll(strings.ReplaceAll(xmlnsPrefix, "-", "_"), ".", "_")
+	// Schemas embedded in WSDL are keyed by fragment of the WSDL file
+	return strings.NewReplacer("-", "_", ".", "_", "#", "_").Replace(xmlnsPrefix)
+}
+
+func (sch *Schema) xsdtypesImportNeeded() bool {
+	if len(sch.IdentityConstraints()) != 0 {
+		return true
+	}
+	for _, typ := range sch.ExportableSimpleTypes() {
+		if typ.IsList() || typ.IsUnion() || typ.Facets() != nil || typ.WrapsRuntimeType() {
+			return true
+		}
+	}
+	for _, el := range sch.ExportableElements() {
+		if fieldsUseXsdtypes(el.Attributes(), el.Elements()) ||
+			el.IsSubstitutionGroupHead() || el.ContainsSubstitutionGroups() || el.ContainsDerivations() ||
+			el.HasDefaultValues() || el.ContainsConstraints() {
+			return true
+		}
+	}
+	for _, typ := range sch.ExportableComplexTypes() {
+		if fieldsUseXsdtypes(typ.Attributes(), typ.Elements()) || typ.ContainsSubstitutionGroups() ||
+			typ.ContainsDerivations() || typ.IsDerivationBase() || typ.IsDerived() || typ.HasDefaultValues() ||
+			typ.ContainsConstraints() {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldsUseXsdtypes(attributes []Attribute, elements []Element) bool {
+	for _, attribute := range attributes {
+		if strings.Contains(attribute.GoFieldType(), "xsdtypes.") {
+			return true
+		}
+	}
+	for _, element := range elements {
+		if strings.Contains(element.GoFieldType(), "xsdtypes.") {
+			return true
+		}
+	}
+	return false
 }
 
 func (sch *Schema) GoImportsNeeded() []string {
@@ -309,6 +535,9 @@ func (sch *Schema) GoImportsNeeded() []string {
 	if sch.encodingXmlImportNeeded() {
 		imports = append(imports, "encoding/xml")
 	}
+	if sch.xsdtypesImportNeeded() {
+		imports = append(imports, xsdtypesPackage)
+	}
 	for _, importedMod := range sch.importedModules {
 		imports = append(imports, fmt.Sprintf("%s/%s", sch.ModulesPath, importedMod.GoPackageName()))
 	}
@@ -321,14 +550,15 @@ func (sch *Schema) registerImportedModule(module *Schema) {
 }
 
 // Some elements are not defined at the top-level, rather these are inlined in the complexType definitions.
-func (sch *Schema) registerInlinedElement(el *Element, parentElement *Element) {
+func (sch *Schema) registerInlinedElement(el *Element, parentElement *Element) error {
 	if sch.isElementInlined(el) {
 		if el.Name == "" {
-			panic("Not implemented: found inlined xsd:element without @name attribute")
+			return fmt.Errorf("%w: found inlined xsd:element without @name attribute", ErrNotImplemented)
 		}
 		el.prefixNameWithParent(parentElement)
 		sch.inlinedElements = append(sch.inlinedElements, *el)
 	}
+	return nil
 }
 
 func (sch *Schema) isElementInlined(el *Element) bool {
@@ -351,8 +581,8 @@ type Import struct {
 }
 
 func (i *Import) load(ws *Workspace, baseDir string) (err error) {
-	if i.SchemaLocation != "" {
-		i.ImportedSchema, err = ws.loadXsd(filepath.Join(baseDir, i.SchemaLocation), false)
+	if file := ws.locate(baseDir, i.SchemaLocation, i.Namespace); file != "" {
+		i.ImportedSchema, err = ws.loadXsd(file, false)
 	}
 	return
 }
@@ -365,8 +595,8 @@ type Include struct {
 }
 
 func (i *Include) load(ws *Workspace, baseDir string) (err error) {
-	if i.SchemaLocation != "" {
-		i.IncludedSchema, err = ws.loadXsd(filepath.Join(baseDir, i.SchemaLocation), true)
+	if file := ws.locate(baseDir, i.SchemaLocation, ""); file != "" {
+		i.IncludedSchema, err = ws.loadXsd(file, true)
 	}
 	return
 }
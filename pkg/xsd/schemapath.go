@@ -0,0 +1,137 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// namespaceRegistry locates schemas imported only by their namespace. These are embedded in the WSDL converted,
+// given explicitly, or found by scanning the schema path for XSD files of matching targetNamespace.
+type namespaceRegistry struct {
+	wsdlSchemas map[string]string   // key of the schema embedded in WSDL by its targetNamespace
+	locations   map[string]string   // explicitly given file by namespace
+	schemaPath  []string            // directories (within the workspace filesystem) to be scanned
+	scanned     map[string][]string // files found on the schema path by their targetNamespace
+}
+
+// locateNamespace returns the file defining the namespace.
+func (ws *Workspace) locateNamespace(namespace string) (string, bool) {
+	reg := &ws.namespaces
+	if file, found := reg.wsdlSchemas[namespace]; found {
+		return file, true
+	}
+	if file, found := reg.locations[namespace]; found {
+		return file, true
+	}
+	if reg.scanned == nil {
+		reg.scanned = map[string][]string{}
+		for _, dir := range reg.schemaPath {
+			ws.scanSchemaPath(dir)
+		}
+	}
+	candidates := reg.scanned[namespace]
+	if len(candidates) == 0 {
+		return "", false
+	}
+	if len(candidates) > 1 {
+		ws.Diagnostics.addWarning(candidates[0], "namespace '%s' is defined by multiple schemas on the schema path, using %s instead of %s",
+			namespace, candidates[0], strings.Join(candidates[1:], ", "))
+	}
+	return candidates[0], true
+}
+
+// scanSchemaPath records targetNamespace of each XSD file within the directory. Files brought in by xsd:include,
+// xsd:redefine or xsd:override of another file in the same namespace are left out, as these are not complete schemas.
+func (ws *Workspace) scanSchemaPath(dir string) {
+	type scannedFile struct {
+		namespace string
+		includes  []string
+	}
+	files := map[string]scannedFile{}
+	err := fs.WalkDir(ws.fsys, dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(file, ".xsd") {
+			return err
+		}
+		data, err := fs.ReadFile(ws.fsys, file)
+		if err != nil {
+			return err
+		}
+		namespace, locations, ok := scanSchemaHeader(data)
+		if !ok {
+			return nil
+		}
+		scanned := scannedFile{namespace: namespace}
+		for _, location := range locations {
+			scanned.includes = append(scanned.includes, ws.resolveLocation(ws.dir(file), location))
+		}
+		files[file] = scanned
+		return nil
+	})
+	if err != nil {
+		ws.Diagnostics.addWarning(dir, "could not scan schema path: %v", err)
+	}
+
+	included := map[string]bool{}
+	for _, scanned := range files {
+		for _, include := range scanned.includes {
+			included[include] = true
+		}
+	}
+	names := make([]string, 0, len(files))
+	for file := range files {
+		names = append(names, file)
+	}
+	sort.Strings(names)
+	for _, file := range names {
+		namespace := files[file].namespace
+		if !included[file] {
+			ws.namespaces.scanned[namespace] = append(ws.namespaces.scanned[namespace], file)
+		}
+	}
+}
+
+// scanSchemaHeader returns targetNamespace of the schema and locations of the documents it includes, without
+// parsing the whole schema.
+func scanSchemaHeader(data []byte) (string, []string, bool) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = charset.NewReaderLabel
+	namespace, found := "", false
+	locations := []string{}
+	for depth := 0; ; {
+		token, err := d.Token()
+		if err != nil {
+			return namespace, locations, found
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				if t.Name.Local != "schema" {
+					return "", nil, false
+				}
+				found = true
+				for _, attr := range t.Attr {
+					if attr.Name.Space == "" && attr.Name.Local == "targetNamespace" {
+						namespace = attr.Value
+					}
+				}
+			} else if depth == 2 {
+				switch t.Name.Local {
+				case "include", "redefine", "override":
+					for _, attr := range t.Attr {
+						if attr.Name.Space == "" && attr.Name.Local == "schemaLocation" {
+							locations = append(locations, attr.Value)
+						}
+					}
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
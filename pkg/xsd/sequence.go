@@ -2,32 +2,99 @@ package xsd
 
 import (
 	"encoding/xml"
+	"errors"
 )
 
 type Sequence struct {
 	XMLName     xml.Name  `xml:"http://www.w3.org/2001/XMLSchema sequence"`
 	ElementList []Element `xml:"element"`
 	Choices     []Choice  `xml:"choice"`
+	Groups      []Group   `xml:"group"`
+	Any         []Any     `xml:"any"`
+	particles   []particle
 	allElements []Element
 }
 
+// particle locates child of xsd:sequence within the list of its kind, so that the document order is kept.
+type particle struct {
+	kind string
+	idx  int
+}
+
 func (s *Sequence) Elements() []Element {
 	return s.allElements
 }
 
-func (s *Sequence) compile(sch *Schema, parentElement *Element) {
-	for idx := range s.ElementList {
-		el := &s.ElementList[idx]
-		el.compile(sch, parentElement)
+// UnmarshalXML records the document order of the particles, as the order of elements within the sequence matters.
+func (s *Sequence) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s.XMLName = start.Name
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "element":
+				err = decodeParticle(d, t, &s.ElementList, &s.particles)
+			case "choice":
+				err = decodeParticle(d, t, &s.Choices, &s.particles)
+			case "group":
+				err = decodeParticle(d, t, &s.Groups, &s.particles)
+			case "any":
+				err = decodeParticle(d, t, &s.Any, &s.particles)
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
 	}
-	s.allElements = s.ElementList
+}
 
-	for idx := range s.Choices {
-		c := &s.Choices[idx]
-		c.compile(sch, parentElement)
+func decodeParticle[T any](d *xml.Decoder, start xml.StartElement, list *[]T, particles *[]particle) error {
+	var value T
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	*particles = append(*particles, particle{kind: start.Name.Local, idx: len(*list)})
+	*list = append(*list, value)
+	return nil
+}
 
-		s.allElements = append(s.allElements, c.Elements()...)
+func (s *Sequence) compile(sch *Schema, parentElement *Element) error {
+	var errs []error
+	s.allElements = []Element{}
+	for _, p := range s.particles {
+		switch p.kind {
+		case "element":
+			el := &s.ElementList[p.idx]
+			if err := el.compile(sch, parentElement); err != nil {
+				errs = append(errs, within(indexedStep("element", p.idx), err))
+			}
+			s.allElements = append(s.allElements, *el)
+		case "choice":
+			c := &s.Choices[p.idx]
+			if err := c.compile(sch, parentElement); err != nil {
+				errs = append(errs, within(indexedStep("choice", p.idx), err))
+			}
+			s.allElements = append(s.allElements, c.Elements()...)
+		case "group":
+			g := &s.Groups[p.idx]
+			if err := g.compile(sch, parentElement); err != nil {
+				errs = append(errs, within(indexedStep("group", p.idx), err))
+			}
+			s.allElements = append(s.allElements, g.Elements()...)
+		case "any":
+			s.Any[p.idx].compile(sch)
+			s.allElements = append(s.allElements, s.Any[p.idx].element())
+		}
 	}
+	return errors.Join(errs...)
 }
 
 type SequenceAll struct {
@@ -41,17 +108,23 @@ func (s *SequenceAll) Elements() []Element {
 	return s.allElements
 }
 
-func (s *SequenceAll) compile(sch *Schema, parentElement *Element) {
+func (s *SequenceAll) compile(sch *Schema, parentElement *Element) error {
+	var errs []error
 	for idx := range s.ElementList {
 		el := &s.ElementList[idx]
-		el.compile(sch, parentElement)
+		if err := el.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("element", idx), err))
+		}
 	}
 	s.allElements = s.ElementList
 
 	for idx := range s.Choices {
 		c := &s.Choices[idx]
-		c.compile(sch, parentElement)
+		if err := c.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("choice", idx), err))
+		}
 
 		s.allElements = append(s.allElements, c.Elements()...)
 	}
+	return errors.Join(errs...)
 }
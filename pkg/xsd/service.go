@@ -0,0 +1,440 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/net/html/charset"
+)
+
+const soapPackage = "github.com/gocomply/xsd2go/pkg/soap"
+
+// wsdlDocument holds the WSDL 1.1 components SOAP stubs are generated from.
+type wsdlDocument struct {
+	XMLName         xml.Name            `xml:"http://schemas.xmlsoap.org/wsdl/ definitions"`
+	TargetNamespace string              `xml:"targetNamespace,attr"`
+	Messages        []wsdlMessage       `xml:"http://schemas.xmlsoap.org/wsdl/ message"`
+	PortTypes       []wsdlPortType      `xml:"http://schemas.xmlsoap.org/wsdl/ portType"`
+	Bindings        []wsdlBinding       `xml:"http://schemas.xmlsoap.org/wsdl/ binding"`
+	Services        []wsdlService       `xml:"http://schemas.xmlsoap.org/wsdl/ service"`
+	xmlns           Xmlns               `xml:"-"`
+	defaultXmlns    string              `xml:"-"`
+	filePath        string              `xml:"-"`
+	positions       map[string]position `xml:"-"`
+}
+
+type wsdlMessage struct {
+	Name  string `xml:"name,attr"`
+	Parts []struct {
+		Name    string `xml:"name,attr"`
+		Element string `xml:"element,attr"`
+		Type    string `xml:"type,attr"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ part"`
+}
+
+type wsdlPortType struct {
+	Name       string `xml:"name,attr"`
+	Operations []struct {
+		Name   string           `xml:"name,attr"`
+		Input  *wsdlMessageRef  `xml:"http://schemas.xmlsoap.org/wsdl/ input"`
+		Output *wsdlMessageRef  `xml:"http://schemas.xmlsoap.org/wsdl/ output"`
+		Faults []wsdlMessageRef `xml:"http://schemas.xmlsoap.org/wsdl/ fault"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+}
+
+type wsdlMessageRef struct {
+	Name    string `xml:"name,attr"`
+	Message string `xml:"message,attr"`
+}
+
+type wsdlBinding struct {
+	Name       string         `xml:"name,attr"`
+	Type       string         `xml:"type,attr"`
+	Soap11     *wsdlSoapAttrs `xml:"http://schemas.xmlsoap.org/wsdl/soap/ binding"`
+	Soap12     *wsdlSoapAttrs `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ binding"`
+	Operations []struct {
+		Name   string         `xml:"name,attr"`
+		Soap11 *wsdlSoapAttrs `xml:"http://schemas.xmlsoap.org/wsdl/soap/ operation"`
+		Soap12 *wsdlSoapAttrs `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ operation"`
+		Input  *wsdlSoapBody  `xml:"http://schemas.xmlsoap.org/wsdl/ input"`
+		Output *wsdlSoapBody  `xml:"http://schemas.xmlsoap.org/wsdl/ output"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ operation"`
+}
+
+// wsdlSoapAttrs are attributes of soap:binding and soap:operation extensions, of either SOAP version.
+type wsdlSoapAttrs struct {
+	Style  string `xml:"style,attr"`
+	Action string `xml:"soapAction,attr"`
+}
+
+type wsdlSoapBody struct {
+	Soap11 *struct {
+		Use string `xml:"use,attr"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/soap/ body"`
+	Soap12 *struct {
+		Use string `xml:"use,attr"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ body"`
+}
+
+func (b *wsdlSoapBody) encoded() bool {
+	return b != nil && ((b.Soap11 != nil && b.Soap11.Use == "encoded") || (b.Soap12 != nil && b.Soap12.Use == "encoded"))
+}
+
+type wsdlService struct {
+	Name  string `xml:"name,attr"`
+	Ports []struct {
+		Name    string `xml:"name,attr"`
+		Binding string `xml:"binding,attr"`
+		Soap11  *struct {
+			Location string `xml:"location,attr"`
+		} `xml:"http://schemas.xmlsoap.org/wsdl/soap/ address"`
+		Soap12 *struct {
+			Location string `xml:"location,attr"`
+		} `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ address"`
+	} `xml:"http://schemas.xmlsoap.org/wsdl/ port"`
+}
+
+// readWsdlDocument parses the WSDL 1.1 components, the embedded schemas are left out.
+func readWsdlDocument(wsdlPath string, data []byte) (*wsdlDocument, error) {
+	doc := &wsdlDocument{filePath: wsdlPath, positions: scanPositions(wsdlPath, bytes.NewReader(data), 0)}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = charset.NewReaderLabel
+	if err := d.Decode(doc); err != nil {
+		return nil, fmt.Errorf("error decoding WSDL: %w; while processing %s", err, wsdlPath)
+	}
+	return doc, nil
+}
+
+func (doc *wsdlDocument) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	doc.xmlns = parseXmlns(start)
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			doc.defaultXmlns = attr.Value
+		}
+	}
+	type w wsdlDocument
+	return d.DecodeElement((*w)(doc), &start)
+}
+
+// qname resolves the qualified name given by attribute of WSDL component.
+func (doc *wsdlDocument) qname(value string) (xml.Name, error) {
+	ref := reference(value)
+	if ref.NsPrefix() == "" {
+		return xml.Name{Space: doc.defaultXmlns, Local: ref.Name()}, nil
+	}
+	uri := doc.xmlns.UriByPrefix(ref.NsPrefix())
+	if uri == "" {
+		return xml.Name{}, fmt.Errorf("%w: xmlns prefix '%s'", ErrUnresolved, ref.NsPrefix())
+	}
+	return xml.Name{Space: uri, Local: ref.Name()}, nil
+}
+
+func (doc *wsdlDocument) message(ref string) (*wsdlMessage, error) {
+	name, err := doc.qname(ref)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range doc.Messages {
+		if doc.Messages[idx].Name == name.Local {
+			return &doc.Messages[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: message %s", ErrUnresolved, ref)
+}
+
+// report records the problem of WSDL component, located by its path within the document.
+func (doc *wsdlDocument) report(severity Severity, diagnostics *Diagnostics, err error, steps ...string) {
+	var component *componentError
+	if errors.As(err, &component) {
+		steps = append(steps, component.steps...)
+		err = component.err
+	}
+	ce := &CompileError{File: doc.filePath, Path: strings.Join(steps, "/"), Err: err}
+	if pos, found := lookupPosition(doc.positions, steps); found {
+		ce.Line, ce.Column = pos.line, pos.column
+	}
+	if severity == SeverityError {
+		diagnostics.addError(ce)
+		return
+	}
+	diagnostics.add(Diagnostic{
+		Severity: SeverityWarning, File: ce.File, Line: ce.Line, Column: ce.Column, Path: ce.Path, Message: err.Error(),
+	})
+}
+
+// Service holds SOAP stubs of WSDL document: go interfaces of its port types, and clients and handlers of their
+// SOAP bindings.
+type Service struct {
+	TargetNamespace string
+	PortTypes       []*PortType
+	Bindings        []*Binding
+	Ports           []Port
+	goPackageName   string
+	modulesPath     string
+	imports         map[string]bool
+}
+
+// PortType is implemented by the go services, operations of its SOAP bindings dispatch to the methods.
+type PortType struct {
+	Name       string
+	Operations []*Operation
+}
+
+// Operation of document/literal style, messages of which consist of single element.
+type Operation struct {
+	Name   string
+	Input  *MessageElement
+	Output *MessageElement // nil for one-way operations
+	Faults []*MessageElement
+}
+
+// MessageElement is the element of message body, modelled by generated go type.
+type MessageElement struct {
+	Namespace string
+	Name      string
+	GoType    string
+}
+
+type Binding struct {
+	Name        string
+	PortType    *PortType
+	Version     string // SOAP version, 1.1 or 1.2
+	Operations  []BoundOperation
+	Unsupported []*Operation // operations of the port type left out, the client fails to call these
+}
+
+type BoundOperation struct {
+	*Operation
+	Action string
+}
+
+// Port gives the address of SOAP binding.
+type Port struct {
+	Name    string
+	Address string
+}
+
+func (svc *Service) GoPackageName() string {
+	return svc.goPackageName
+}
+
+func (svc *Service) GoImportsNeeded() []string {
+	imports := []string{}
+	for imp := range svc.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+func (pt *PortType) GoName() string {
+	return strcase.ToCamel(pt.Name)
+}
+
+func (op *Operation) GoName() string {
+	return strcase.ToCamel(op.Name)
+}
+
+func (b *Binding) GoName() string {
+	return strcase.ToCamel(b.Name)
+}
+
+func (b *Binding) GoVersion() string {
+	if b.Version == "1.2" {
+		return "soap.V12"
+	}
+	return "soap.V11"
+}
+
+// GoOperationVar names the variable describing the bound operation for the soap package.
+func (b *Binding) GoOperationVar(op BoundOperation) string {
+	return strcase.ToLowerCamel(b.Name) + op.GoName() + "Operation"
+}
+
+func (p Port) GoName() string {
+	return strcase.ToCamel(p.Name)
+}
+
+// compileService builds SOAP stubs of the WSDL document once its embedded schemas are compiled. Operations that
+// are not of document/literal style are left out.
+func (ws *Workspace) compileService(doc *wsdlDocument) {
+	svc := &Service{TargetNamespace: doc.TargetNamespace, modulesPath: ws.GoModulesPath, imports: map[string]bool{}}
+	svc.goPackageName = ws.xmlnsOverrides.override(doc.TargetNamespace)
+	if svc.goPackageName == "" {
+		if sch := ws.schemaByNamespace(doc.TargetNamespace); sch != nil {
+			svc.goPackageName = sch.GoPackageName()
+		} else {
+			base := strings.TrimSuffix(path.Base(filepath.ToSlash(doc.filePath)), ".wsdl")
+			svc.goPackageName = strings.NewReplacer("-", "_", ".", "_").Replace(base)
+		}
+	}
+
+	portTypes := map[string]*PortType{}
+	for _, wpt := range doc.PortTypes {
+		pt := &PortType{Name: wpt.Name}
+		for opIdx, wop := range wpt.Operations {
+			steps := []string{namedStep("portType", wpt.Name), indexedStep("operation", opIdx)}
+			op, err := ws.compileOperation(doc, svc, wop.Name, wop.Input, wop.Output, wop.Faults)
+			if errors.Is(err, ErrNotImplemented) {
+				doc.report(SeverityWarning, ws.Diagnostics, fmt.Errorf("operation %s is left out: %w", wop.Name, err), steps...)
+				continue
+			}
+			if err != nil {
+				doc.report(SeverityError, ws.Diagnostics, err, steps...)
+				continue
+			}
+			pt.Operations = append(pt.Operations, op)
+		}
+		svc.PortTypes = append(svc.PortTypes, pt)
+		portTypes[wpt.Name] = pt
+	}
+
+	for bIdx, wb := range doc.Bindings {
+		if wb.Soap11 == nil && wb.Soap12 == nil {
+			// Only SOAP bindings are supported, the others (e.g. HTTP GET) are left out silently
+			continue
+		}
+		step := namedStep("binding", wb.Name)
+		ptName, err := doc.qname(wb.Type)
+		pt := portTypes[ptName.Local]
+		if err == nil && pt == nil {
+			err = fmt.Errorf("%w: portType %s", ErrUnresolved, wb.Type)
+		}
+		if err != nil {
+			doc.report(SeverityError, ws.Diagnostics, err, step)
+			continue
+		}
+		b := &Binding{Name: wb.Name, PortType: pt, Version: "1.1"}
+		style := ""
+		if wb.Soap11 != nil {
+			style = wb.Soap11.Style
+		} else {
+			b.Version = "1.2"
+			style = wb.Soap12.Style
+		}
+		for _, op := range pt.Operations {
+			bound := BoundOperation{Operation: op}
+			opStyle := style
+			for idx, wop := range doc.Bindings[bIdx].Operations {
+				if wop.Name != op.Name {
+					continue
+				}
+				for _, attrs := range []*wsdlSoapAttrs{wop.Soap11, wop.Soap12} {
+					if attrs != nil {
+						bound.Action = attrs.Action
+						if attrs.Style != "" {
+							opStyle = attrs.Style
+						}
+					}
+				}
+				if wop.Input.encoded() || wop.Output.encoded() {
+					opStyle = "encoded"
+				}
+				if opStyle != "" && opStyle != "document" {
+					doc.report(SeverityWarning, ws.Diagnostics,
+						fmt.Errorf("operation %s is left out: %w: %s style", op.Name, ErrNotImplemented, opStyle),
+						step, indexedStep("operation", idx))
+				}
+			}
+			if opStyle == "" || opStyle == "document" {
+				b.Operations = append(b.Operations, bound)
+			} else {
+				b.Unsupported = append(b.Unsupported, op)
+			}
+		}
+		svc.Bindings = append(svc.Bindings, b)
+	}
+
+	for _, service := range doc.Services {
+		for _, port := range service.Ports {
+			if port.Soap11 != nil {
+				svc.Ports = append(svc.Ports, Port{Name: port.Name, Address: port.Soap11.Location})
+			} else if port.Soap12 != nil {
+				svc.Ports = append(svc.Ports, Port{Name: port.Name, Address: port.Soap12.Location})
+			}
+		}
+	}
+
+	if len(svc.PortTypes) == 0 {
+		return
+	}
+	svc.imports["context"] = true
+	for _, b := range svc.Bindings {
+		svc.imports["encoding/xml"] = true
+		svc.imports["net/http"] = true
+		svc.imports[soapPackage] = true
+		if len(b.Unsupported) != 0 {
+			svc.imports["fmt"] = true
+		}
+	}
+	ws.Services = append(ws.Services, svc)
+}
+
+func (ws *Workspace) compileOperation(doc *wsdlDocument, svc *Service, name string, input, output *wsdlMessageRef, faults []wsdlMessageRef) (*Operation, error) {
+	if input == nil {
+		return nil, fmt.Errorf("%w: operation without input", ErrNotImplemented)
+	}
+	op := &Operation{Name: name}
+	var err error
+	if op.Input, err = ws.messageElement(doc, svc, input.Message); err != nil {
+		return nil, within("input", err)
+	}
+	if output != nil {
+		if op.Output, err = ws.messageElement(doc, svc, output.Message); err != nil {
+			return nil, within("output", err)
+		}
+	}
+	for idx, fault := range faults {
+		element, err := ws.messageElement(doc, svc, fault.Message)
+		if err != nil {
+			return nil, within(indexedStep("fault", idx), err)
+		}
+		op.Faults = append(op.Faults, element)
+	}
+	return op, nil
+}
+
+// messageElement resolves the element the message of document/literal operation consists of.
+func (ws *Workspace) messageElement(doc *wsdlDocument, svc *Service, ref string) (*MessageElement, error) {
+	msg, err := doc.message(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.Parts) != 1 || msg.Parts[0].Element == "" {
+		return nil, fmt.Errorf("%w: message %s does not consist of single element part", ErrNotImplemented, msg.Name)
+	}
+	name, err := doc.qname(msg.Parts[0].Element)
+	if err != nil {
+		return nil, err
+	}
+	sch := ws.schemaByNamespace(name.Space)
+	if sch == nil {
+		return nil, fmt.Errorf("%w: schema of namespace '%s'", ErrUnresolved, name.Space)
+	}
+	el := sch.GetElement(name.Local)
+	if el == nil {
+		return nil, fmt.Errorf("%w: element %s", ErrUnresolved, msg.Parts[0].Element)
+	}
+	goType := el.GoName()
+	if sch.GoPackageName() != svc.goPackageName {
+		goType = sch.GoPackageName() + "." + goType
+		svc.imports[fmt.Sprintf("%s/%s", svc.modulesPath, sch.GoPackageName())] = true
+	}
+	return &MessageElement{Namespace: name.Space, Name: name.Local, GoType: goType}, nil
+}
+
+func (ws *Workspace) schemaByNamespace(namespace string) *Schema {
+	for _, sch := range ws.Cache {
+		if sch.TargetNamespace == namespace {
+			return sch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package xsd
+
+import (
+	"sort"
+	"strings"
+)
+
+// IsSubstitutionGroupHead reports whether other elements may appear in place of this element.
+func (e *Element) IsSubstitutionGroupHead() bool {
+	return e.Abstract || len(e.substitutes) != 0
+}
+
+// ReferencesSubstitutionGroup reports whether this element is a reference to substitution group head.
+func (e *Element) ReferencesSubstitutionGroup() bool {
+	return e.refElm != nil && e.refElm.IsSubstitutionGroupHead()
+}
+
+// Go interface implemented by all the members of substitution group headed by this element.
+func (e *Element) GoSubstituteInterface() string {
+	return e.GoName() + "Substitute"
+}
+
+// Go registry of all the members of substitution group headed by this element.
+func (e *Element) GoSubstituteRegistry() string {
+	return e.GoSubstituteInterface() + "s"
+}
+
+// SubstitutionGroups lists heads of all the substitution groups this element is a member of.
+func (e *Element) SubstitutionGroups() []*Element {
+	heads := []*Element{}
+	if e.IsSubstitutionGroupHead() {
+		heads = append(heads, e)
+	}
+	return append(heads, e.allSubstitutionHeads()...)
+}
+
+// Members of substitution group headed by this element that are defined in the same go package.
+func (e *Element) LocalSubstitutes() []*Element {
+	res := []*Element{}
+	for _, substitute := range e.SubstitutionGroupMembers() {
+		if substitute.schema.TargetNamespace == e.schema.TargetNamespace {
+			res = append(res, substitute)
+		}
+	}
+	return res
+}
+
+// SubstitutionGroupMembers lists all the elements that may appear in place of this element, including
+// the element itself unless it is abstract.
+func (e *Element) SubstitutionGroupMembers() []*Element {
+	res := []*Element{}
+	if !e.Abstract {
+		res = append(res, e)
+	}
+	for _, substitute := range e.substitutes {
+		res = append(res, substitute.SubstitutionGroupMembers()...)
+	}
+	return res
+}
+
+func (e *Element) allSubstitutionHeads() []*Element {
+	res := []*Element{}
+	for _, head := range e.substitutionHeads {
+		res = append(res, head)
+		res = append(res, head.allSubstitutionHeads()...)
+	}
+	return res
+}
+
+func (e *Element) compileSubstitutionGroup() error {
+	for _, headRef := range strings.Fields(e.SubstitutionGroup) {
+		head, err := e.schema.findReferencedElement(reference(headRef))
+		if err != nil {
+			return err
+		}
+		e.substitutionHeads = append(e.substitutionHeads, head)
+		head.substitutes = append(head.substitutes, e)
+	}
+	return nil
+}
+
+// ForeignSubstitution pairs an element with substitution group head defined in another go package.
+type ForeignSubstitution struct {
+	Head   *Element
+	Member *Element
+}
+
+func (fs *ForeignSubstitution) GoHeadPackage() string {
+	return fs.Head.schema.GoPackageName()
+}
+
+// ForeignSubstitutions lists elements of this schema that belong to substitution groups headed elsewhere.
+// Such elements need to be registered with the head's package at runtime.
+func (sch *Schema) ForeignSubstitutions() []ForeignSubstitution {
+	res := []ForeignSubstitution{}
+	for idx := range sch.Elements {
+		el := &sch.Elements[idx]
+		if el.Abstract {
+			continue
+		}
+		for _, head := range el.allSubstitutionHeads() {
+			if head.schema.TargetNamespace != sch.TargetNamespace {
+				res = append(res, ForeignSubstitution{Head: head, Member: el})
+			}
+		}
+	}
+	return res
+}
+
+func (ws *Workspace) compileSubstitutionGroups() {
+	for _, schema := range ws.Cache {
+		for idx := range schema.Elements {
+			el := &schema.Elements[idx]
+			if el.SubstitutionGroup != "" {
+				if err := el.compileSubstitutionGroup(); err != nil {
+					schema.reportError(withinTopLevel(schema, namedStep("element", el.Name), err))
+				}
+			}
+		}
+	}
+	for _, schema := range ws.Cache {
+		for idx := range schema.Elements {
+			el := &schema.Elements[idx]
+			sort.Slice(el.substitutes, func(i, j int) bool {
+				return el.substitutes[i].Name < el.substitutes[j].Name
+			})
+			for _, head := range el.allSubstitutionHeads() {
+				if head.schema.TargetNamespace != schema.TargetNamespace {
+					schema.registerImportedModule(head.schema)
+				}
+			}
+		}
+	}
+}
+
+// ContainsSubstitutionGroups reports whether the golang type needs custom unmarshalling of substitution group members.
+func (e *Element) ContainsSubstitutionGroups() bool {
+	return containsSubstitutionGroups(e.Elements())
+}
+
+func (ct *ComplexType) ContainsSubstitutionGroups() bool {
+	return containsSubstitutionGroups(ct.Elements())
+}
+
+func containsSubstitutionGroups(elements []Element) bool {
+	for idx := range elements {
+		if elements[idx].ReferencesSubstitutionGroup() {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespace of top-level element.
+func (e *Element) Namespace() string {
+	return e.schema.TargetNamespace
+}
@@ -2,6 +2,9 @@ package xsd
 
 import (
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/iancoleman/strcase"
 )
@@ -13,7 +16,7 @@ type Type interface {
 	Attributes() []Attribute
 	Elements() []Element
 	ContainsText() bool
-	compile(*Schema, *Element)
+	compile(*Schema, *Element) error
 }
 
 func injectSchemaIntoAttributes(schema *Schema, intermAttributes []Attribute) []Attribute {
@@ -50,13 +53,21 @@ type ComplexType struct {
 	SimpleContent    *SimpleContent  `xml:"simpleContent"`
 	ComplexContent   *ComplexContent `xml:"complexContent"`
 	Choice           *Choice         `xml:"choice"`
+	Group            *Group          `xml:"group"`
+	AnyAttribute     *AnyAttribute   `xml:"anyAttribute"`
 	content          GenericContent
+	derivedFrom      *ComplexType
+	derivations      []*ComplexType
+	superseded       bool // original definition referenced by xsd:redefine
 }
 
 func (ct *ComplexType) Attributes() []Attribute {
 	if ct.content != nil {
 		return ct.content.Attributes()
 	}
+	if ct.AnyAttribute != nil {
+		return append(ct.AttributesDirect[:len(ct.AttributesDirect):len(ct.AttributesDirect)], ct.AnyAttribute.attribute())
+	}
 	return ct.AttributesDirect
 }
 
@@ -84,6 +95,10 @@ func (ct *ComplexType) Documentation() string {
 }
 
 func (ct *ComplexType) Elements() []Element {
+	return deduplicateWildcards(ct.elements())
+}
+
+func (ct *ComplexType) elements() []Element {
 	if ct.Sequence != nil {
 		return setXmlNameAnyForSingleElements(ct.Sequence.Elements())
 	} else if ct.SequenceAll != nil {
@@ -92,6 +107,8 @@ func (ct *ComplexType) Elements() []Element {
 		return setXmlNameAnyForSingleElements(ct.content.Elements())
 	} else if ct.Choice != nil {
 		return ct.Choice.Elements()
+	} else if ct.Group != nil {
+		return setXmlNameAnyForSingleElements(ct.Group.Elements())
 	}
 	return []Element{}
 }
@@ -112,71 +129,119 @@ func (ct *ComplexType) ContainsText() bool {
 	return ct.content != nil && ct.content.ContainsText()
 }
 
+// GoTextType returns golang type of the text content of simpleContent (or mixed complexContent) extension.
+func (ct *ComplexType) GoTextType() string {
+	switch content := ct.content.(type) {
+	case *SimpleContent:
+		if content.Extension != nil {
+			return content.Extension.GoTextType()
+		}
+	case *ComplexContent:
+		if content.Extension != nil {
+			return content.Extension.GoTextType()
+		}
+	}
+	return "string"
+}
+
 func (ct *ComplexType) Schema() *Schema {
 	return ct.schema
 }
 
-func (ct *ComplexType) compile(sch *Schema, parentElement *Element) {
+func (ct *ComplexType) compile(sch *Schema, parentElement *Element) error {
 	ct.schema = sch
+	var errs []error
+	if ct.AnyAttribute != nil {
+		ct.AnyAttribute.compile(sch)
+	}
 	if ct.Sequence != nil {
-		ct.Sequence.compile(sch, parentElement)
+		if err := ct.Sequence.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("sequence", err))
+		}
 	}
 	if ct.SequenceAll != nil {
 		if ct.Sequence != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:sequence and xsd:all")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:sequence and xsd:all", ErrNotImplemented)
+		}
+		if err := ct.SequenceAll.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("all", err))
 		}
-		ct.SequenceAll.compile(sch, parentElement)
 	}
 
 	// Handle improbable name clash. Consider XSD defining two attributes on the element:
 	// "id" and "Id", this would create name clash given the camelization we do.
 	goNames := map[string]uint{}
-	for idx := range ct.Attributes() {
-		attribute := &ct.Attributes()[idx]
-		attribute.compile(sch)
+	for idx := range ct.AttributesDirect {
+		attribute := &ct.AttributesDirect[idx]
+		if err := attribute.compile(sch); err != nil {
+			errs = append(errs, within(indexedStep("attribute", idx), err))
+		}
 
 		count := goNames[attribute.GoName()]
 		count += 1
 		goNames[attribute.GoName()] = count
 		attribute.DuplicateCount = count
+		if count > 1 {
+			sch.warn("attribute '%s' clashes with another attribute once camelized, generated as %s", attribute.Name, attribute.GoName())
+		}
 		// Second GoName may be different depending on the DuplicateCount
 		goNames[attribute.GoName()] = count
 	}
 
+	contentStep := ""
 	if ct.ComplexContent != nil {
 		ct.content = ct.ComplexContent
+		contentStep = "complexContent"
 		if ct.SimpleContent != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:simpleContent and xsd:complexContent together")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:simpleContent and xsd:complexContent together", ErrNotImplemented)
 		}
 	} else if ct.SimpleContent != nil {
 		ct.content = ct.SimpleContent
+		contentStep = "simpleContent"
 	}
 
 	if ct.content != nil {
 		if len(ct.AttributesDirect) > 1 {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines direct attribute and xsd:content")
+			return fmt.Errorf("%w: xsd:complexType defines direct attribute and xsd:content", ErrNotImplemented)
 		}
 		if ct.Sequence != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:sequence and xsd:content")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:sequence and xsd:content", ErrNotImplemented)
 		}
 		if ct.SequenceAll != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:all and xsd:content")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:all and xsd:content", ErrNotImplemented)
+		}
+		if err := ct.content.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(contentStep, err))
 		}
-		ct.content.compile(sch, parentElement)
 	}
 
 	if ct.Choice != nil {
 		if ct.content != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:choice and xsd:content")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:choice and xsd:content", ErrNotImplemented)
 		}
 		if ct.Sequence != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:choice and xsd:sequence")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:choice and xsd:sequence", ErrNotImplemented)
 		}
 		if ct.SequenceAll != nil {
-			panic("Not implemented: xsd:complexType " + ct.Name + " defines xsd:all and xsd:sequence")
+			return fmt.Errorf("%w: xsd:complexType defines xsd:all and xsd:sequence", ErrNotImplemented)
+		}
+		if err := ct.Choice.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("choice", err))
+		}
+	}
+
+	if ct.Group != nil {
+		if ct.content != nil {
+			return fmt.Errorf("%w: xsd:complexType defines xsd:group and xsd:content", ErrNotImplemented)
+		}
+		if ct.Sequence != nil || ct.SequenceAll != nil || ct.Choice != nil {
+			return fmt.Errorf("%w: xsd:complexType defines xsd:group and another model group", ErrNotImplemented)
+		}
+		if err := ct.Group.compile(sch, parentElement); err != nil {
+			errs = append(errs, within("group", err))
 		}
-		ct.Choice.compile(sch, parentElement)
 	}
+	return errors.Join(errs...)
 }
 
 type SimpleType struct {
@@ -184,7 +249,10 @@ type SimpleType struct {
 	Name        string       `xml:"name,attr"`
 	Annotation  *Annotation  `xml:"annotation"`
 	Restriction *Restriction `xml:"restriction"`
+	List        *List        `xml:"list"`
+	Union       *Union       `xml:"union"`
 	schema      *Schema
+	superseded  bool // original definition referenced by xsd:redefine
 }
 
 func (st *SimpleType) GoName() string {
@@ -192,24 +260,99 @@ func (st *SimpleType) GoName() string {
 }
 
 func (st *SimpleType) GoTypeName() string {
+	if st.List != nil {
+		return "[]" + st.List.GoItemTypeName()
+	}
+	if u := st.union(); u != nil {
+		if u.enumerationOnly() {
+			return "string"
+		}
+		return st.GoName()
+	}
 	if st.Restriction != nil && st.Restriction.typ != nil {
 		return st.Restriction.typ.GoTypeName()
 	}
 	return "string"
 }
 
+// WrapsRuntimeType reports whether the simple type restricts a type of xsdtypes package. The golang type embeds
+// the runtime type, so that it keeps its methods.
+func (st *SimpleType) WrapsRuntimeType() bool {
+	return st.List == nil && st.union() == nil && strings.HasPrefix(st.GoTypeName(), "xsdtypes.")
+}
+
 func (st *SimpleType) Schema() *Schema {
 	return st.schema
 }
 
-func (st *SimpleType) compile(sch *Schema, parentElement *Element) {
+func (st *SimpleType) compile(sch *Schema, parentElement *Element) error {
 	if st.schema == nil {
 		st.schema = sch
 	}
 
 	if st.Restriction != nil {
-		st.Restriction.compile(sch, parentElement)
+		if st.List != nil {
+			return fmt.Errorf("%w: xsd:simpleType defines xsd:restriction and xsd:list together", ErrNotImplemented)
+		}
+		if err := st.Restriction.compile(sch, parentElement); err != nil {
+			return within("restriction", err)
+		}
 	}
+	if st.List != nil {
+		if err := st.List.compile(sch, parentElement); err != nil {
+			return within("list", err)
+		}
+	}
+	if st.Union != nil {
+		if st.Restriction != nil || st.List != nil {
+			return fmt.Errorf("%w: xsd:simpleType defines xsd:union together with xsd:restriction or xsd:list", ErrNotImplemented)
+		}
+		if err := st.Union.compile(sch, parentElement); err != nil {
+			return within("union", err)
+		}
+	}
+	return nil
+}
+
+// Union of member types that given type is, either directly or by restriction.
+func (st *SimpleType) union() *Union {
+	if st.Union != nil {
+		return st.Union
+	}
+	if st.Restriction != nil {
+		if base, ok := st.Restriction.typ.(*SimpleType); ok {
+			return base.union()
+		}
+	}
+	return nil
+}
+
+// IsUnion reports whether this type needs to be represented by golang type that tracks active member type.
+func (st *SimpleType) IsUnion() bool {
+	u := st.union()
+	return u != nil && !u.enumerationOnly()
+}
+
+func (st *SimpleType) UnionMembers() []UnionMember {
+	members := []UnionMember{}
+	if u := st.union(); u != nil {
+		for _, member := range u.members {
+			members = append(members, UnionMember{unionMember: member, schema: st.schema})
+		}
+	}
+	return members
+}
+
+// IsList reports whether values of this type are whitespace separated lists, either directly or by restriction.
+func (st *SimpleType) IsList() bool {
+	if st.List != nil {
+		return true
+	}
+	if st.Restriction != nil {
+		base, ok := st.Restriction.typ.(*SimpleType)
+		return ok && base.IsList()
+	}
+	return false
 }
 
 func (*SimpleType) Attributes() []Attribute {
@@ -235,9 +378,12 @@ func (*SimpleType) Elements() []Element {
 }
 
 func (st *SimpleType) Enums() []Enumeration {
-	if st.Restriction != nil {
+	if st.Restriction != nil && len(st.Restriction.Enums()) != 0 {
 		return st.Restriction.Enums()
 	}
+	if u := st.union(); u != nil && u.enumerationOnly() {
+		return u.Enums()
+	}
 	return []Enumeration{}
 }
 
@@ -271,7 +417,8 @@ func (staticType) ContainsText() bool {
 	return true
 }
 
-func (staticType) compile(*Schema, *Element) {
+func (staticType) compile(*Schema, *Element) error {
+	return nil
 }
 
 var staticTypes = map[string]staticType{
@@ -280,12 +427,15 @@ var staticTypes = map[string]staticType{
 	"dateTime":           "string",
 	"date":               "string",
 	"base64Binary":       "string",
-	"duration":           "string",
+	"duration":           "xsdtypes.Duration",
+	"dayTimeDuration":    "xsdtypes.DayTimeDuration",
+	"yearMonthDuration":  "xsdtypes.YearMonthDuration",
 	"normalizedString":   "string",
 	"token":              "string",
 	"Name":               "string",
 	"NCName":             "string",
-	"NMTOKENS":           "string",
+	"NMTOKEN":            "string",
+	"ENTITY":             "string",
 	"anySimpleType":      "string",
 	"anyType":            "string",
 	"int":                "int",
@@ -318,15 +468,73 @@ var staticTypes = map[string]staticType{
 	"QName":              "string",
 }
 
-func StaticType(name string) staticType {
+// Arbitrary precision types of xsdtypes package, which replace the lossy static types when requested by
+// WorkspaceOptions.PreciseNumbers
+var preciseStaticTypes = map[string]staticType{
+	"decimal":            "xsdtypes.Decimal",
+	"integer":            "xsdtypes.Integer",
+	"negativeInteger":    "xsdtypes.NegativeInteger",
+	"nonNegativeInteger": "xsdtypes.NonNegativeInteger",
+	"nonPositiveInteger": "xsdtypes.NonPositiveInteger",
+	"positiveInteger":    "xsdtypes.PositiveInteger",
+}
+
+// Byte slices of xsdtypes package, which decode binary data when requested by WorkspaceOptions.BinaryTypes
+var binaryStaticTypes = map[string]staticType{
+	"base64Binary": "xsdtypes.Base64Binary",
+	"hexBinary":    "xsdtypes.HexBinary",
+}
+
+// Qualified names of xsdtypes package, which resolve the prefixes when requested by WorkspaceOptions.QNameTypes
+var qnameStaticTypes = map[string]staticType{
+	"QName": "xsdtypes.QName",
+}
+
+// Static types which golang counterparts cannot represent all the values of the XSD type (arbitrary precision)
+var lossyStaticTypes = map[string]bool{
+	"decimal":            true,
+	"integer":            true,
+	"negativeInteger":    true,
+	"nonNegativeInteger": true,
+	"nonPositiveInteger": true,
+	"positiveInteger":    true,
+}
+
+// Date and time types, which are generated as types of xsdtypes package when requested by WorkspaceOptions.TimeTypes
+var timeStaticTypes = map[string]staticType{
+	"dateTime":   "xsdtypes.DateTime",
+	"date":       "xsdtypes.Date",
+	"time":       "xsdtypes.Time",
+	"gYear":      "xsdtypes.GYear",
+	"gYearMonth": "xsdtypes.GYearMonth",
+	"gMonthDay":  "xsdtypes.GMonthDay",
+	"gDay":       "xsdtypes.GDay",
+	"gMonth":     "xsdtypes.GMonth",
+}
+
+func StaticType(name string) (staticType, error) {
 	typ, found := staticTypes[name]
 	if found {
-		return typ
+		return typ, nil
 	}
-	panic("Type xsd:" + name + " not implemented")
+	return "", fmt.Errorf("%w: type xsd:%s", ErrNotImplemented, name)
 }
 
 func IsStaticType(name string) bool {
 	_, found := staticTypes[name]
 	return found
 }
+
+// Go type name of the given XSD type, qualified by its package name when referenced from another schema.
+func goTypeReference(typ Type, from *Schema) string {
+	if st, ok := typ.(*SimpleType); ok && st.Name == "" {
+		// Anonymous simple types are represented by their underlying golang type
+		return st.GoTypeName()
+	}
+	foreignSchema := typ.Schema()
+	if foreignSchema != nil && foreignSchema != from &&
+		foreignSchema.TargetNamespace != from.TargetNamespace {
+		return foreignSchema.GoPackageName() + "." + typ.GoName()
+	}
+	return typ.GoName()
+}
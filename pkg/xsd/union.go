@@ -0,0 +1,132 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Union defines simple type which values belong to any of its member types (xsd:union).
+type Union struct {
+	XMLName     xml.Name     `xml:"http://www.w3.org/2001/XMLSchema union"`
+	MemberTypes string       `xml:"memberTypes,attr"`
+	SimpleTypes []SimpleType `xml:"simpleType"`
+	schema      *Schema
+	members     []unionMember
+}
+
+type unionMember struct {
+	name    string
+	typ     Type
+	builtin string // name of built-in member type
+}
+
+// UnionMember represents single member type of xsd:union as seen from the generated golang type.
+type UnionMember struct {
+	unionMember
+	schema *Schema
+}
+
+// Public Go Name of this member, used to name accessor methods.
+func (m *UnionMember) GoName() string {
+	return m.name
+}
+
+func (m *UnionMember) GoTypeName() string {
+	return goTypeReference(m.typ, m.schema)
+}
+
+func (m *UnionMember) Enums() []Enumeration {
+	if st, ok := m.typ.(*SimpleType); ok {
+		return st.Enums()
+	}
+	return []Enumeration{}
+}
+
+// WhiteSpace returns whiteSpace facet of the member type, the text is normalized by it before it is tried against
+// the member type.
+func (m *UnionMember) WhiteSpace() string {
+	if st, ok := m.typ.(*SimpleType); ok {
+		return st.facets().WhiteSpace
+	}
+	if m.builtin == "normalizedString" {
+		return "replace"
+	}
+	if collapsedStringTypes[m.builtin] {
+		return "collapse"
+	}
+	return ""
+}
+
+func (u *Union) compile(sch *Schema, parentElement *Element) error {
+	u.schema = sch
+	u.members = []unionMember{}
+	var errs []error
+	for _, memberType := range strings.Fields(u.MemberTypes) {
+		ref := reference(memberType)
+		typ, err := sch.findReferencedType(ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		builtin := ""
+		if _, static := typ.(staticType); static {
+			builtin = ref.Name()
+		}
+		u.addMember(strcase.ToCamel(ref.Name()), typ, builtin)
+	}
+	for idx := range u.SimpleTypes {
+		st := &u.SimpleTypes[idx]
+		if err := st.compile(sch, parentElement); err != nil {
+			errs = append(errs, within(indexedStep("simpleType", idx), err))
+		}
+		name := st.GoName()
+		if name == "" {
+			name = fmt.Sprintf("Member%d", len(u.members)+1)
+		}
+		u.addMember(name, st, "")
+	}
+	if len(u.members) == 0 {
+		return fmt.Errorf("%w: xsd:union defines neither ./@memberTypes nor ./xsd:simpleType", ErrNotImplemented)
+	}
+	return errors.Join(errs...)
+}
+
+func (u *Union) addMember(name string, typ Type, builtin string) {
+	// Member names are used for accessor methods, thus these need to be unique
+	for _, member := range u.members {
+		if member.name == name {
+			name = fmt.Sprintf("%s%d", name, len(u.members)+1)
+			break
+		}
+	}
+	u.members = append(u.members, unionMember{name: name, typ: typ, builtin: builtin})
+}
+
+// Union consisting only of enumerated member types can be represented by plain set of constants.
+func (u *Union) enumerationOnly() bool {
+	for _, member := range u.members {
+		st, ok := member.typ.(*SimpleType)
+		if !ok || len(st.Enums()) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (u *Union) Enums() []Enumeration {
+	seen := map[string]bool{}
+	enums := []Enumeration{}
+	for _, member := range u.members {
+		for _, enum := range member.typ.(*SimpleType).Enums() {
+			if !seen[enum.Value] {
+				seen[enum.Value] = true
+				enums = append(enums, enum)
+			}
+		}
+	}
+	return enums
+}
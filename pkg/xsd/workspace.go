@@ -2,31 +2,111 @@ package xsd
 
 import (
 	"fmt"
-	"path/filepath"
+	"io/fs"
+	"log/slog"
+	"maps"
 )
 
 type Workspace struct {
 	Cache          map[string]*Schema // Parsed XSD schemas by its filename (user specifies initial one, and we load dependencies)
 	GoModulesPath  string             // user requested go package path (example: github.com/gocomply/scap)
 	xmlnsOverrides xmlnsOverrides     // user-supplied xmlns overrides
+	Diagnostics    *Diagnostics       // problems found in all the schemas
+	Services       []*Service         // SOAP stubs of the WSDL converted
+	logger         *slog.Logger
+	fsys           fs.FS
+	catalogs       []*catalog
+	fetcher        Fetcher
+	namespaces     namespaceRegistry
+	embedded       map[string]*Schema // schemas embedded in WSDL, by their key, until these are loaded
+	wsdl           *wsdlDocument
+	staticTypes    map[string]staticType // runtime types replacing the default static types, as given by the options
+}
+
+// WorkspaceOptions customize loading of the schemas into the workspace.
+type WorkspaceOptions struct {
+	XmlnsOverrides []string     // go package names for XML namespaces, in form of XMLNS=GOPKGNAME
+	Logger         *slog.Logger // logs the schemas loaded, nothing is logged if nil
+	FS             fs.FS        // filesystem the schemas are read from, the local one if nil
+	Catalogs       []string     // OASIS XML Catalog files (within FS) mapping schemaLocation and namespaces to files
+	Fetcher        Fetcher      // downloads schemas given by URL, these are not loaded if nil
+	// Schemas imported without schemaLocation are found by their namespace, within the files given explicitly or
+	// within the XSD files found in the schema path directories (within FS)
+	SchemaLocations map[string]string
+	SchemaPath      []string
+	// xsd:dateTime, xsd:date, xsd:time and xsd:g* types are generated as types of xsdtypes package, instead of string
+	TimeTypes bool
+	// xsd:decimal and the unbounded xsd:integer types are generated as arbitrary precision types of xsdtypes
+	// package, instead of float64 and int64
+	PreciseNumbers bool
+	// xsd:base64Binary and xsd:hexBinary are generated as byte slices of xsdtypes package, instead of string
+	BinaryTypes bool
+	// xsd:QName is generated as type of xsdtypes package, which resolves the prefix to namespace, instead of string
+	QNameTypes bool
 }
 
 func NewWorkspace(goModulesPath, xsdPath string, xmlnsOverrides []string) (*Workspace, error) {
+	return NewWorkspaceWithOptions(goModulesPath, xsdPath, WorkspaceOptions{XmlnsOverrides: xmlnsOverrides})
+}
+
+func NewWorkspaceWithOptions(goModulesPath, xsdPath string, opts WorkspaceOptions) (*Workspace, error) {
 	ws := Workspace{
 		Cache:         map[string]*Schema{},
+		embedded:      map[string]*Schema{},
 		GoModulesPath: goModulesPath,
+		Diagnostics:   &Diagnostics{},
+		logger:        opts.Logger,
+		fsys:          opts.FS,
+		fetcher:       opts.Fetcher,
+		namespaces:    namespaceRegistry{locations: opts.SchemaLocations, schemaPath: opts.SchemaPath},
+		staticTypes:   map[string]staticType{},
+	}
+	if ws.fsys == nil {
+		ws.fsys = osFS{}
+	}
+	if ws.logger == nil {
+		ws.logger = slog.New(slog.DiscardHandler)
+	}
+	for _, mapping := range []struct {
+		enabled bool
+		types   map[string]staticType
+	}{
+		{opts.TimeTypes, timeStaticTypes},
+		{opts.PreciseNumbers, preciseStaticTypes},
+		{opts.BinaryTypes, binaryStaticTypes},
+		{opts.QNameTypes, qnameStaticTypes},
+	} {
+		if mapping.enabled {
+			maps.Copy(ws.staticTypes, mapping.types)
+		}
 	}
 	var err error
-	ws.xmlnsOverrides, err = ParseXmlnsOverrides(xmlnsOverrides)
+	ws.xmlnsOverrides, err = ParseXmlnsOverrides(opts.XmlnsOverrides)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = ws.loadXsd(xsdPath, false)
+	loaded := map[string]*catalog{}
+	for _, file := range opts.Catalogs {
+		c, err := ws.loadCatalog(file, loaded)
+		if err != nil {
+			ws.Diagnostics.addError(err)
+			return &ws, ws.Diagnostics.Err()
+		}
+		ws.catalogs = append(ws.catalogs, c)
+	}
+
+	if isWsdl(xsdPath) {
+		err = ws.loadWsdl(xsdPath)
+	} else {
+		_, err = ws.loadXsd(xsdPath, false)
+	}
 	if err != nil {
-		return nil, err
+		ws.Diagnostics.addError(err)
+	} else {
+		ws.compile()
 	}
-	return &ws, ws.compile()
+	return &ws, ws.Diagnostics.Err()
 }
 
 func (ws *Workspace) loadXsd(xsdPath string, shouldBeInlined bool) (*Schema, error) {
@@ -34,16 +114,24 @@ func (ws *Workspace) loadXsd(xsdPath string, shouldBeInlined bool) (*Schema, err
 	if found {
 		return cached, nil
 	}
-	fmt.Println("\tParsing:", xsdPath)
+	schema, embedded := ws.embedded[xsdPath]
+	if embedded {
+		delete(ws.embedded, xsdPath)
+	} else {
+		ws.logger.Info("parsing schema", "file", xsdPath)
 
-	schema, err := ReadSchemaFromFile(xsdPath)
-	if err != nil {
-		return nil, err
+		var err error
+		schema, err = ws.readSchema(xsdPath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	schema.ModulesPath = ws.GoModulesPath
+	schema.diagnostics = ws.Diagnostics
 	schema.filePath = xsdPath
 	schema.goPackageNameOverride = ws.xmlnsOverrides.override(schema.TargetNamespace)
+	schema.staticTypeOverrides = ws.staticTypes
 
 	if !shouldBeInlined {
 		// Cache all loaded schemas in the workspace, unless it was brought in by xsd:include element.
@@ -51,47 +139,57 @@ func (ws *Workspace) loadXsd(xsdPath string, shouldBeInlined bool) (*Schema, err
 		ws.Cache[xsdPath] = schema
 	}
 
-	dir := filepath.Dir(xsdPath)
+	dir := ws.dir(xsdPath)
 
 	for idx := range schema.Includes {
 		si := schema.Includes[idx]
 		if err := si.load(ws, dir); err != nil {
 			return nil, err
 		}
-
-		isch := si.IncludedSchema
-		schema.Imports = append(isch.Imports, schema.Imports...)
-		schema.Elements = append(isch.Elements, schema.Elements...)
-		schema.Attributes = append(isch.Attributes, schema.Attributes...)
-		schema.AttributeGroups = append(isch.AttributeGroups, schema.AttributeGroups...)
-		schema.ComplexTypes = append(isch.ComplexTypes, schema.ComplexTypes...)
-		schema.SimpleTypes = append(isch.SimpleTypes, schema.SimpleTypes...)
-		schema.inlinedElements = append(isch.inlinedElements, schema.inlinedElements...)
-		for key, sch := range isch.importedModules {
-			schema.importedModules[key] = sch
+		schema.include(si.IncludedSchema)
+	}
+	for _, redefines := range [][]Redefine{schema.Redefines, schema.Overrides} {
+		for idx := range redefines {
+			sr := &redefines[idx]
+			if err := sr.load(ws, dir); err != nil {
+				return nil, err
+			}
+			schema.include(sr.redefinedSchema(schema))
 		}
 	}
 
 	for idx := range schema.Imports {
-		if err := schema.Imports[idx].load(ws, dir); err != nil {
+		imp := &schema.Imports[idx]
+		if err := imp.load(ws, dir); err != nil {
 			return nil, err
 		}
+		if imp.ImportedSchema == nil && imp.Namespace != "" {
+			schema.warn("no schema found for xsd:import of namespace '%s', consider adding its directory to the schema path", imp.Namespace)
+		}
 	}
 	schema.compile()
 	return schema, nil
 }
 
-func (ws *Workspace) compile() error {
+func (ws *Workspace) compile() {
+	ws.compileSubstitutionGroups()
+	ws.compileDerivations()
+
 	uniqPkgNames := map[string]string{}
 
 	for _, schema := range ws.Cache {
 		goPackageName := schema.GoPackageName()
 		prevXmlns, dupeFound := uniqPkgNames[goPackageName]
 		if dupeFound {
-			return fmt.Errorf("malformed workspace; multiple XSD files refer to itself with xmlns shorthand: '%s':\n - %s\n - %s\nWhile this is valid in XSD it is impractical for golang code generation.\nConsider providing --xmlns-override=%s=mygopackage", goPackageName, prevXmlns, schema.TargetNamespace, schema.TargetNamespace)
+			ws.Diagnostics.addError(&CompileError{
+				File: schema.filePath,
+				Err:  fmt.Errorf("malformed workspace; multiple XSD files refer to itself with xmlns shorthand: '%s':\n - %s\n - %s\nWhile this is valid in XSD it is impractical for golang code generation.\nConsider providing --xmlns-override=%s=mygopackage", goPackageName, prevXmlns, schema.TargetNamespace, schema.TargetNamespace),
+			})
+			continue
 		}
 		uniqPkgNames[goPackageName] = schema.TargetNamespace
 	}
-
-	return nil
+	if ws.wsdl != nil {
+		ws.compileService(ws.wsdl)
+	}
 }
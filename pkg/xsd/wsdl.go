@@ -0,0 +1,128 @@
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	wsdl11Namespace = "http://schemas.xmlsoap.org/wsdl/"
+	wsdl20Namespace = "http://www.w3.org/ns/wsdl"
+	xsdNamespace    = "http://www.w3.org/2001/XMLSchema"
+)
+
+func isWsdl(file string) bool {
+	return strings.HasSuffix(strings.ToLower(file), ".wsdl")
+}
+
+// loadWsdl loads the schemas embedded in types of WSDL 1.1 or WSDL 2.0 document, as if these were standalone XSD
+// files. The embedded schemas are keyed by the WSDL file and their index, these import each other by namespace.
+func (ws *Workspace) loadWsdl(wsdlPath string) error {
+	ws.logger.Info("parsing wsdl", "file", wsdlPath)
+	data, err := ws.readFile(wsdlPath)
+	if err != nil {
+		return err
+	}
+	schemas, root, err := readWsdl(wsdlPath, data)
+	if err != nil {
+		return err
+	}
+
+	ws.namespaces.wsdlSchemas = map[string]string{}
+	keys := []string{}
+	primary := map[string]*Schema{}
+	for idx, schema := range schemas {
+		if first, found := primary[schema.TargetNamespace]; found {
+			// Types of a single namespace are often split into multiple schemas, these make up a single package
+			first.merge(schema)
+			continue
+		}
+		primary[schema.TargetNamespace] = schema
+		key := fmt.Sprintf("%s#schema%d", wsdlPath, idx+1)
+		ws.embedded[key] = schema
+		ws.namespaces.wsdlSchemas[schema.TargetNamespace] = key
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		if _, err := ws.loadXsd(key, false); err != nil {
+			return err
+		}
+	}
+
+	if root.Space != wsdl11Namespace {
+		var description struct {
+			Interfaces []struct{} `xml:"http://www.w3.org/ns/wsdl interface"`
+		}
+		if xml.Unmarshal(data, &description) == nil && len(description.Interfaces) != 0 {
+			ws.Diagnostics.addWarning(wsdlPath, "SOAP stubs are generated for WSDL 1.1 documents only")
+		}
+		return nil
+	}
+	ws.wsdl, err = readWsdlDocument(wsdlPath, data)
+	return err
+}
+
+// readWsdl parses xsd:schema elements found within the types of WSDL document. The embedded schemas inherit the
+// namespace declarations of the enclosing WSDL elements. The root element tells version of WSDL.
+func readWsdl(wsdlPath string, data []byte) ([]*Schema, xml.Name, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = charset.NewReaderLabel
+
+	schemas := []*Schema{}
+	root := xml.Name{}
+	ancestors := []xml.StartElement{}
+	for {
+		offset := d.InputOffset()
+		token, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xml.Name{}, fmt.Errorf("error decoding WSDL: %w; while processing %s", err, wsdlPath)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(ancestors) == 0 && t.Name != (xml.Name{Space: wsdl11Namespace, Local: "definitions"}) &&
+				t.Name != (xml.Name{Space: wsdl20Namespace, Local: "description"}) {
+				return nil, xml.Name{}, fmt.Errorf("%s is not a WSDL document, found root element %s", wsdlPath, t.Name.Local)
+			}
+			if len(ancestors) == 0 {
+				root = t.Name
+			}
+			if len(ancestors) == 2 && ancestors[1].Name.Local == "types" && t.Name == (xml.Name{Space: xsdNamespace, Local: "schema"}) {
+				schema := &Schema{importedModules: map[string]*Schema{}, diagnostics: &Diagnostics{}}
+				if err := d.DecodeElement(schema, &t); err != nil {
+					return nil, xml.Name{}, fmt.Errorf("error decoding XSD: %w; while processing %s", err, wsdlPath)
+				}
+				for idx := len(ancestors) - 1; idx >= 0; idx-- {
+					schema.Xmlns = schema.Xmlns.inherit(parseXmlns(ancestors[idx]))
+				}
+				schema.positions = scanPositions(wsdlPath, bytes.NewReader(data), offset)
+				schemas = append(schemas, schema)
+				continue
+			}
+			ancestors = append(ancestors, t)
+		case xml.EndElement:
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+	}
+	if len(schemas) == 0 {
+		return nil, xml.Name{}, fmt.Errorf("no xsd:schema found within types of WSDL %s", wsdlPath)
+	}
+	return schemas, root, nil
+}
+
+// merge adds top-level components of another schema of the same namespace embedded in the same WSDL.
+func (sch *Schema) merge(other *Schema) {
+	sch.Includes = append(sch.Includes, other.Includes...)
+	sch.Redefines = append(sch.Redefines, other.Redefines...)
+	sch.Overrides = append(sch.Overrides, other.Overrides...)
+	sch.Xmlns = sch.Xmlns.inherit(other.Xmlns)
+	sch.include(other)
+}
@@ -44,3 +44,13 @@ func (declarations Xmlns) UriByPrefix(prefix string) string {
 	}
 	return ""
 }
+
+// inherit adds declarations of the enclosing element, unless these prefixes are declared already.
+func (declarations Xmlns) inherit(enclosing Xmlns) Xmlns {
+	for _, declaration := range enclosing {
+		if declarations.UriByPrefix(declaration.Prefix) == "" {
+			declarations = append(declarations, declaration)
+		}
+	}
+	return declarations
+}
@@ -1,27 +1,23 @@
 package xsd2go
 
-import (
-	"fmt"
-
-	"github.com/gocomply/xsd2go/pkg/template"
-	"github.com/gocomply/xsd2go/pkg/xsd"
-)
+import "github.com/gocomply/xsd2go/pkg/xsd"
 
+// Convert generates golang code for the given XSD and the schemas it brings in. Schema that cannot be compiled is
+// reported by *xsd.CompileError locating the faulty component. Warnings are not reported and progress is not
+// logged, see ConvertWithDiagnostics and Generator for more options (e.g. Options.Logger).
 func Convert(xsdPath, goModule, outputDir string, xmlnsOverrides []string) error {
-	fmt.Printf("Processing '%s'\n", xsdPath)
-	ws, err := xsd.NewWorkspace(fmt.Sprintf("%s/%s", goModule, outputDir), xsdPath, xmlnsOverrides)
-	if err != nil {
-		return err
-	}
-
-	for _, sch := range ws.Cache {
-		if sch.Empty() {
-			continue
-		}
-		if err := template.GenerateTypes(sch, outputDir); err != nil {
-			return err
-		}
-	}
+	_, err := ConvertWithDiagnostics(xsdPath, goModule, outputDir, xmlnsOverrides)
+	return err
+}
 
-	return nil
+// ConvertWithDiagnostics works as Convert, while it returns all the problems found in the schemas, including
+// the warnings. The code is generated only when none of the problems is an error.
+func ConvertWithDiagnostics(xsdPath, goModule, outputDir string, xmlnsOverrides []string) ([]xsd.Diagnostic, error) {
+	res, err := NewGenerator(Options{
+		GoModule:       goModule,
+		OutputDir:      outputDir,
+		XmlnsOverrides: xmlnsOverrides,
+		Output:         DirWriter(outputDir),
+	}).Generate(xsdPath)
+	return res.Diagnostics, err
 }
@@ -0,0 +1,145 @@
+package xsd2go
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/gocomply/xsd2go/pkg/template"
+	"github.com/gocomply/xsd2go/pkg/xsd"
+)
+
+// Options of the go code generation.
+type Options struct {
+	GoModule       string            // import path of the go module the generated packages belong to
+	OutputDir      string            // directory of the generated packages, relative to the root of GoModule
+	XmlnsOverrides []string          // go package names for XML namespaces, in form of XMLNS=GOPKGNAME
+	PackageNames   map[string]string // go package names by XML namespace, complementing XmlnsOverrides
+	FileName       string            // name of the go file generated in each package, models.go if empty
+	FS             fs.FS             // filesystem the schemas are read from, the local one if nil
+	Catalogs       []string          // OASIS XML Catalog files (within FS) mapping schemaLocation and namespaces to files
+	Fetcher        xsd.Fetcher       // downloads schemas given by URL, these are not loaded if nil
+	// Schemas imported without schemaLocation are found by their namespace, within the files given explicitly or
+	// within the XSD files found in the schema path directories (within FS)
+	SchemaLocations map[string]string
+	SchemaPath      []string
+	TimeTypes       bool         // date and time types are generated as types of xsdtypes package, instead of string
+	PreciseNumbers  bool         // decimal and unbounded integer types are generated as arbitrary precision types of xsdtypes package
+	BinaryTypes     bool         // base64Binary and hexBinary are generated as byte slices of xsdtypes package, instead of string
+	QNameTypes      bool         // QName is generated as type of xsdtypes package resolving the namespace, instead of string
+	Logger          *slog.Logger // logs progress of the generation, nothing is logged if nil
+	Output          FileWriter   // receives the generated files, which are returned by Result.Files either way
+}
+
+// FileWriter receives the generated files, named by slash-separated paths relative to the output directory.
+type FileWriter interface {
+	WriteFile(name string, data []byte) error
+}
+
+// DirWriter writes the generated files into the directory on disk.
+type DirWriter string
+
+func (dir DirWriter) WriteFile(name string, data []byte) error {
+	file := filepath.Join(string(dir), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), os.FileMode(0722)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, data, 0o666); err != nil {
+		return fmt.Errorf("could not create '%s': %w", file, err)
+	}
+	return nil
+}
+
+// Result of the go code generation.
+type Result struct {
+	Files       map[string][]byte // generated go files, named as given to Options.Output
+	Diagnostics []xsd.Diagnostic  // problems found in the schemas
+}
+
+// Generator generates go code for XSD schemas.
+type Generator struct {
+	opts Options
+}
+
+func NewGenerator(opts Options) *Generator {
+	if opts.FileName == "" {
+		opts.FileName = template.FileName
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	return &Generator{opts: opts}
+}
+
+// Generate generates go code for the XSD and the schemas it brings in. The code is generated only when none of
+// the problems found in the schemas is an error, the returned result lists these problems either way.
+func (g *Generator) Generate(xsdPath string) (*Result, error) {
+	g.opts.Logger.Info("processing schema", "file", xsdPath)
+	overrides := append([]string{}, g.opts.XmlnsOverrides...)
+	for xmlns, pkg := range g.opts.PackageNames {
+		overrides = append(overrides, xmlns+"="+pkg)
+	}
+	res := &Result{Files: map[string][]byte{}, Diagnostics: []xsd.Diagnostic{}}
+	ws, err := xsd.NewWorkspaceWithOptions(fmt.Sprintf("%s/%s", g.opts.GoModule, g.opts.OutputDir), xsdPath, xsd.WorkspaceOptions{
+		XmlnsOverrides:  overrides,
+		Logger:          g.opts.Logger,
+		FS:              g.opts.FS,
+		Catalogs:        g.opts.Catalogs,
+		Fetcher:         g.opts.Fetcher,
+		SchemaLocations: g.opts.SchemaLocations,
+		SchemaPath:      g.opts.SchemaPath,
+		TimeTypes:       g.opts.TimeTypes,
+		PreciseNumbers:  g.opts.PreciseNumbers,
+		BinaryTypes:     g.opts.BinaryTypes,
+		QNameTypes:      g.opts.QNameTypes,
+	})
+	if ws == nil {
+		return res, err
+	}
+	res.Diagnostics = ws.Diagnostics.Entries()
+	if err != nil {
+		return res, err
+	}
+
+	files := []string{}
+	for file := range ws.Cache {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		sch := ws.Cache[file]
+		if sch.Empty() {
+			continue
+		}
+		code, err := template.RenderTypes(sch)
+		if err != nil {
+			return res, err
+		}
+		if err := g.output(res, path.Join(sch.GoPackageName(), g.opts.FileName), code); err != nil {
+			return res, err
+		}
+	}
+	for _, svc := range ws.Services {
+		code, err := template.RenderService(svc)
+		if err != nil {
+			return res, err
+		}
+		if err := g.output(res, path.Join(svc.GoPackageName(), template.ServiceFileName), code); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func (g *Generator) output(res *Result, name string, code []byte) error {
+	res.Files[name] = code
+	if g.opts.Output == nil {
+		return nil
+	}
+	g.opts.Logger.Info("generating", "file", name)
+	return g.opts.Output.WriteFile(name, code)
+}
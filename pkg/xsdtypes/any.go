@@ -0,0 +1,117 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AnyElement holds arbitrary XML element matched by xsd:any wildcard.
+type AnyElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	InnerXML string     `xml:",innerxml"`
+}
+
+// MarshalXML preserves namespace declarations of the element, so that prefixes used by its inner XML remain bound.
+func (a AnyElement) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if a.XMLName.Local != "" {
+		start.Name = a.XMLName
+	}
+	start.Attr = make([]xml.Attr, 0, len(a.Attrs))
+	for _, attr := range a.Attrs {
+		switch {
+		case attr.Name.Space == "xmlns":
+			attr.Name = xml.Name{Local: "xmlns:" + attr.Name.Local}
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			// Default namespace is declared by encoding/xml based on the element name
+			continue
+		}
+		start.Attr = append(start.Attr, attr)
+	}
+	return e.EncodeElement(struct {
+		InnerXML string `xml:",innerxml"`
+	}{a.InnerXML}, start)
+}
+
+// ErrWildcardNamespace is reported by Validate for element or attribute which namespace is not allowed by the
+// wildcard that matched it.
+var ErrWildcardNamespace = errors.New("namespace not allowed by wildcard")
+
+// Wildcard is namespace constraint of xsd:any or xsd:anyAttribute.
+type Wildcard struct {
+	Namespace       string // ##any (or empty), ##other or list of namespaces, ##targetNamespace and ##local
+	TargetNamespace string // target namespace of the schema declaring the wildcard
+}
+
+// Allows reports whether the wildcard matches element or attribute of the given namespace (empty if unqualified).
+func (w Wildcard) Allows(namespace string) bool {
+	if w.Namespace == "" {
+		return true
+	}
+	for _, allowed := range strings.Fields(w.Namespace) {
+		switch allowed {
+		case "##any":
+			return true
+		case "##other":
+			if namespace != "" && namespace != w.TargetNamespace {
+				return true
+			}
+		case "##targetNamespace":
+			if namespace == w.TargetNamespace {
+				return true
+			}
+		case "##local":
+			if namespace == "" {
+				return true
+			}
+		default:
+			if namespace == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w Wildcard) check(path string, name xml.Name) error {
+	if w.Allows(name.Space) {
+		return nil
+	}
+	return &ValidationError{Path: path, Err: fmt.Errorf("%w: {%s}%s", ErrWildcardNamespace, name.Space, name.Local)}
+}
+
+// WildcardElements holds the elements matched by xsd:any wildcard, so that Validate checks their namespaces.
+type WildcardElements struct {
+	Wildcard Wildcard
+	Elements []AnyElement
+}
+
+func (w WildcardElements) Validate() error {
+	for _, element := range w.Elements {
+		if err := w.Wildcard.check(element.XMLName.Local, element.XMLName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WildcardAttrs holds the attributes matched by xsd:anyAttribute wildcard, so that Validate checks their namespaces.
+// Namespace declarations and attributes of XML Schema instance namespace are not subject to the wildcard.
+type WildcardAttrs struct {
+	Wildcard Wildcard
+	Attrs    []xml.Attr
+}
+
+func (w WildcardAttrs) Validate() error {
+	for _, attr := range w.Attrs {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") || attr.Name.Space == XsiNamespace {
+			continue
+		}
+		if err := w.Wildcard.check("@"+attr.Name.Local, attr.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package xsdtypes
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// BinaryError is returned for malformed lexical representation of binary data.
+type BinaryError struct {
+	Type string
+	Err  error
+}
+
+func (e *BinaryError) Error() string {
+	return fmt.Sprintf("xsdtypes: invalid %s value: %v", e.Type, e.Err)
+}
+
+func (e *BinaryError) Unwrap() error {
+	return e.Err
+}
+
+// Base64Binary holds data of xsd:base64Binary. Whitespace and line breaks within the encoded text are ignored.
+type Base64Binary []byte
+
+// HexBinary holds data of xsd:hexBinary.
+type HexBinary []byte
+
+func (b Base64Binary) MarshalText() ([]byte, error) {
+	res := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(res, b)
+	return res, nil
+}
+
+func (b *Base64Binary) UnmarshalText(text []byte) error {
+	encoded := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(xmlWhitespace, r) {
+			return -1
+		}
+		return r
+	}, string(text))
+	res, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return &BinaryError{Type: "base64Binary", Err: err}
+	}
+	*b = res
+	return nil
+}
+
+// MarshalXMLAttr leaves out the attribute of empty value, which denotes absent optional attribute.
+func (b Base64Binary) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return binaryAttr(name, b)
+}
+
+// length is measured in octets by the length facets.
+func (b Base64Binary) length() int {
+	return len(b)
+}
+
+// MarshalText uses upper-case digits, as the canonical representation of xsd:hexBinary does.
+func (b HexBinary) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(hex.EncodeToString(b))), nil
+}
+
+func (b *HexBinary) UnmarshalText(text []byte) error {
+	res, err := hex.DecodeString(strings.Trim(string(text), xmlWhitespace))
+	if err != nil {
+		return &BinaryError{Type: "hexBinary", Err: err}
+	}
+	*b = res
+	return nil
+}
+
+// MarshalXMLAttr leaves out the attribute of empty value, which denotes absent optional attribute.
+func (b HexBinary) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return binaryAttr(name, b)
+}
+
+// length is measured in octets by the length facets.
+func (b HexBinary) length() int {
+	return len(b)
+}
+
+func binaryAttr(name xml.Name, data encoding.TextMarshaler) (xml.Attr, error) {
+	text, err := data.MarshalText()
+	if err != nil || len(text) == 0 {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
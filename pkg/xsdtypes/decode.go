@@ -0,0 +1,84 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// DecodeElement decodes element given by start into v, while letting dispatch take over decoding of selected
+// child elements. The dispatch function reports whether it has consumed the child element. All the other
+// content is decoded into v by encoding/xml as usual. The dispatch function may be nil.
+//
+// Generated UnmarshalXML methods use DecodeElement for child elements that cannot be described by struct tags.
+func DecodeElement(d *xml.Decoder, start *xml.StartElement, v any, dispatch func(*xml.Decoder, xml.StartElement) (bool, error)) error {
+	_, err := decodeElement(d, start, v, dispatch)
+	return err
+}
+
+// decodeElement returns names of the child elements decoded into v, mapped to whether these are empty.
+// Elements marked by xsi:nil are not considered empty, so that no default value is applied to them.
+func decodeElement(d *xml.Decoder, start *xml.StartElement, v any, dispatch func(*xml.Decoder, xml.StartElement) (bool, error)) (map[xml.Name]bool, error) {
+	r := &childReader{d: d, start: start, dispatch: dispatch, children: map[xml.Name]bool{}}
+	if err := xml.NewTokenDecoder(r).Decode(v); err != nil {
+		return nil, err
+	}
+	return r.children, nil
+}
+
+// childReader passes the tokens of the element through, as these are read from the underlying decoder, while
+// recording its child elements and handing them over to dispatch.
+type childReader struct {
+	d        *xml.Decoder
+	start    *xml.StartElement
+	dispatch func(*xml.Decoder, xml.StartElement) (bool, error)
+	children map[xml.Name]bool
+	child    xml.Name
+	depth    int
+	done     bool
+}
+
+func (r *childReader) Token() (xml.Token, error) {
+	if r.start != nil {
+		start := r.start.Copy()
+		r.start = nil
+		return start, nil
+	}
+	if r.done {
+		return nil, io.EOF
+	}
+	for {
+		tok, err := r.d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if r.depth == 0 {
+				if r.dispatch != nil {
+					handled, err := r.dispatch(r.d, t)
+					if err != nil {
+						return nil, err
+					}
+					if handled {
+						continue
+					}
+				}
+				r.child = t.Name
+				r.children[r.child] = !IsNil(t)
+			} else if r.depth == 1 {
+				r.children[r.child] = false
+			}
+			r.depth++
+		case xml.CharData:
+			if r.depth == 1 {
+				r.children[r.child] = false
+			}
+		case xml.EndElement:
+			if r.depth == 0 {
+				r.done = true
+			}
+			r.depth--
+		}
+		return xml.CopyToken(tok), nil
+	}
+}
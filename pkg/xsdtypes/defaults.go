@@ -0,0 +1,163 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Default describes value given to an attribute or element by the schema, either by default= or by fixed=.
+type Default struct {
+	Name  string // XML name of the attribute (prefixed by @) or element
+	Space string // namespace of the element, empty if it is unqualified
+	Field any    // pointer to the golang field
+	Value string // lexical representation of the value
+	Fixed bool
+}
+
+// FixedValueError is returned when document contradicts value fixed by the schema.
+type FixedValueError struct {
+	Name  string
+	Value string
+	Fixed string
+}
+
+func (e *FixedValueError) Error() string {
+	return fmt.Sprintf("xsdtypes: value '%s' of %s does not match fixed value '%s'", e.Value, e.Name, e.Fixed)
+}
+
+// DecodeDefaults decodes the element as DecodeElement does and applies the values given by the schema afterwards:
+// attributes absent from the start element and child elements present with empty content get their default or
+// fixed values, while fixed values of the other attributes and child elements present are verified. Absent child
+// elements are left unset, generated accessors fall back to their default values.
+func DecodeDefaults(d *xml.Decoder, start *xml.StartElement, v any, defaults []Default, dispatch func(*xml.Decoder, xml.StartElement) (bool, error)) error {
+	children, err := decodeElement(d, start, v, dispatch)
+	if err != nil {
+		return err
+	}
+	for _, def := range defaults {
+		var empty, present bool
+		if name, ok := strings.CutPrefix(def.Name, "@"); ok {
+			present = hasAttr(*start, name)
+			empty = !present
+		} else {
+			empty, present = children[xml.Name{Space: def.Space, Local: def.Name}]
+		}
+		if empty {
+			if err := setValue(def.Field, def.Value); err != nil {
+				return fmt.Errorf("xsdtypes: cannot apply default value of %s: %w", def.Name, err)
+			}
+		} else if present && def.Fixed {
+			if err := checkFixed(def); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyFixed sets fields to their fixed values. Generated MarshalXML methods apply fixed values to the copy
+// of the value being marshalled. Child elements left unset (nil or empty string) are not given any value, as
+// these are either omitted or get the fixed value when decoded.
+func ApplyFixed(defaults []Default) error {
+	for _, d := range defaults {
+		if !d.Fixed || (!strings.HasPrefix(d.Name, "@") && unset(d.Field)) {
+			continue
+		}
+		if err := setValue(d.Field, d.Value); err != nil {
+			return fmt.Errorf("xsdtypes: cannot apply fixed value of %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// OrDefault returns the value of optional field, or the value given by its lexical representation when the field
+// is nil. Zero value is returned, when the lexical representation cannot be parsed. Generated accessors of the
+// elements with default or fixed value call it.
+func OrDefault[T any](field *T, value string) T {
+	if field != nil {
+		return *field
+	}
+	var res T
+	if err := setValue(&res, value); err != nil {
+		var zero T
+		return zero
+	}
+	return res
+}
+
+// checkFixed verifies that the field holds its fixed value.
+func checkFixed(d Default) error {
+	field := reflect.ValueOf(d.Field).Elem()
+	expected := reflect.New(field.Type())
+	if err := setValue(expected.Interface(), d.Value); err != nil {
+		return fmt.Errorf("xsdtypes: cannot parse fixed value of %s: %w", d.Name, err)
+	}
+	actual, fixed := dereference(field), dereference(expected.Elem())
+	if !actual.IsValid() || !fixed.IsValid() || reflect.DeepEqual(actual.Interface(), fixed.Interface()) {
+		return nil
+	}
+	text, _ := MarshalText(actual.Interface())
+	return &FixedValueError{Name: d.Name, Value: text, Fixed: d.Value}
+}
+
+func hasAttr(start xml.StartElement, name string) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name && attr.Name.Space != "xmlns" {
+			return true
+		}
+	}
+	return false
+}
+
+func unset(field any) bool {
+	v := reflect.ValueOf(field).Elem()
+	return (v.Kind() == reflect.Pointer && v.IsNil()) || (v.Kind() == reflect.String && v.Len() == 0)
+}
+
+// valueHolder is implemented by wrappers of values, such as Nillable.
+type valueHolder interface {
+	value() any
+	isNil() bool
+}
+
+func setValue(dst any, text string) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Pointer {
+		// Optional fields get freshly allocated value, the original one may be shared with other struct
+		fresh := reflect.New(v.Elem().Type().Elem())
+		if err := setValue(fresh.Interface(), text); err != nil {
+			return err
+		}
+		v.Elem().Set(fresh)
+		return nil
+	}
+	if holder, ok := dst.(valueHolder); ok {
+		return setValue(holder.value(), text)
+	}
+	return UnmarshalText(dst, text)
+}
+
+// dereference returns the value held by pointers and wrappers, or invalid value if there is none.
+func dereference(v reflect.Value) reflect.Value {
+	for {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+			continue
+		}
+		if v.CanAddr() {
+			if holder, ok := v.Addr().Interface().(valueHolder); ok {
+				if holder.isNil() {
+					return reflect.Value{}
+				}
+				v = reflect.ValueOf(holder.value()).Elem()
+				continue
+			}
+		}
+		return v
+	}
+}
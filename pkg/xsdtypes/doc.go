@@ -0,0 +1,5 @@
+// Package xsdtypes provides runtime support for the golang code generated by xsd2go.
+//
+// Generated models import this package whenever XSD constructs cannot be expressed
+// by plain golang types and encoding/xml struct tags alone.
+package xsdtypes
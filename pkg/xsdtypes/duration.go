@@ -0,0 +1,281 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration holds value of xsd:duration. Years and months are kept apart from days and the time, as these are
+// calendar-relative and cannot be converted to each other. All the components are non-negative, Negative applies
+// to the duration as whole.
+type Duration struct {
+	Negative bool
+	Years    int
+	Months   int
+	Days     int
+	Time     time.Duration // hours, minutes and seconds
+}
+
+// DayTimeDuration holds value of xsd:dayTimeDuration, duration given by days, hours, minutes and seconds only.
+type DayTimeDuration struct {
+	Duration
+}
+
+// YearMonthDuration holds value of xsd:yearMonthDuration, duration given by years and months only.
+type YearMonthDuration struct {
+	Duration
+}
+
+// DurationError is returned for malformed lexical representation of duration.
+type DurationError struct {
+	Type  string
+	Value string
+}
+
+func (e *DurationError) Error() string {
+	return fmt.Sprintf("xsdtypes: invalid %s value '%s'", e.Type, e.Value)
+}
+
+// IsZero reports whether the duration has no length. Zero value of an optional attribute denotes absent attribute.
+func (d Duration) IsZero() bool {
+	return d.Years == 0 && d.Months == 0 && d.Days == 0 && d.Time == 0
+}
+
+// AddTo returns the time shifted by the duration, as specified by XSD: years and months are added first, with
+// the day of month pinned to the length of the resulting month, then the days and the time are added.
+func (d Duration) AddTo(t time.Time) time.Time {
+	sign := 1
+	if d.Negative {
+		sign = -1
+	}
+	year, month, day := t.Date()
+	months := int(month) - 1 + sign*(d.Years*12+d.Months)
+	year += floorDiv(months, 12)
+	month = time.Month(months-floorDiv(months, 12)*12) + 1
+	day = min(day, daysIn(year, month))
+	hour, minute, second := t.Clock()
+	res := time.Date(year, month, day, hour, minute, second, t.Nanosecond(), t.Location())
+	return res.AddDate(0, 0, sign*d.Days).Add(time.Duration(sign) * d.Time)
+}
+
+// Durations are ordered by adding them to the reference times given by XSD. Durations differing in the order
+// for some of these (e.g. P1M and P30D) are not comparable.
+var durationReferenceTimes = []time.Time{
+	time.Date(1696, time.September, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1697, time.February, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1903, time.March, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1903, time.July, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// Compare returns -1, 0 or +1 as the duration is shorter, equal or longer than the other one. False is returned
+// for durations which are not comparable.
+func (d Duration) Compare(other Duration) (int, bool) {
+	res := 0
+	for idx, ref := range durationReferenceTimes {
+		c := d.AddTo(ref).Compare(other.AddTo(ref))
+		if idx != 0 && c != res {
+			return 0, false
+		}
+		res = c
+	}
+	return res, true
+}
+
+func (d Duration) compareText(text string) (int, bool) {
+	other, err := parseDuration("duration", text)
+	if err != nil {
+		return 0, false
+	}
+	return d.Compare(other)
+}
+
+func (d Duration) String() string {
+	if d.IsZero() {
+		return "PT0S"
+	}
+	var b strings.Builder
+	if d.Negative {
+		b.WriteString("-")
+	}
+	b.WriteString("P")
+	for _, c := range []struct {
+		value      int
+		designator string
+	}{{d.Years, "Y"}, {d.Months, "M"}, {d.Days, "D"}} {
+		if c.value != 0 {
+			fmt.Fprintf(&b, "%d%s", c.value, c.designator)
+		}
+	}
+	if d.Time == 0 {
+		return b.String()
+	}
+	b.WriteString("T")
+	hours, rest := d.Time/time.Hour, d.Time%time.Hour
+	minutes, rest := rest/time.Minute, rest%time.Minute
+	if hours != 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes != 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if rest != 0 {
+		fmt.Fprintf(&b, "%d", rest/time.Second)
+		if nanos := rest % time.Second; nanos != 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0"))
+		}
+		b.WriteString("S")
+	}
+	return b.String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	res, err := parseDuration("duration", strings.Trim(string(text), xmlWhitespace))
+	if err != nil {
+		return err
+	}
+	*d = res
+	return nil
+}
+
+// MarshalXMLAttr leaves out the attribute of zero value, which denotes absent optional attribute.
+func (d Duration) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if d.IsZero() {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
+
+func (d *DayTimeDuration) UnmarshalText(text []byte) error {
+	lexical := strings.Trim(string(text), xmlWhitespace)
+	date, _, _ := strings.Cut(lexical, "T")
+	if strings.ContainsAny(date, "YM") {
+		return &DurationError{Type: "dayTimeDuration", Value: lexical}
+	}
+	res, err := parseDuration("dayTimeDuration", lexical)
+	if err != nil {
+		return err
+	}
+	d.Duration = res
+	return nil
+}
+
+func (d *YearMonthDuration) UnmarshalText(text []byte) error {
+	lexical := strings.Trim(string(text), xmlWhitespace)
+	if strings.ContainsAny(lexical, "DT") {
+		return &DurationError{Type: "yearMonthDuration", Value: lexical}
+	}
+	res, err := parseDuration("yearMonthDuration", lexical)
+	if err != nil {
+		return err
+	}
+	d.Duration = res
+	return nil
+}
+
+// parseDuration parses -?PnYnMnDTnHnMnS, where any of the components may be left out, but at least one has to
+// be given. Fractional seconds are truncated to nanoseconds.
+func parseDuration(typeName, text string) (Duration, error) {
+	fail := func() (Duration, error) {
+		return Duration{}, &DurationError{Type: typeName, Value: text}
+	}
+	var d Duration
+	rest, negative := strings.CutPrefix(text, "-")
+	d.Negative = negative
+	rest, found := strings.CutPrefix(rest, "P")
+	if !found || rest == "" {
+		return fail()
+	}
+	date, clock, timeGiven := strings.Cut(rest, "T")
+	if timeGiven && clock == "" {
+		return fail()
+	}
+
+	components := []struct {
+		designator string
+		value      *int
+	}{{"Y", &d.Years}, {"M", &d.Months}, {"D", &d.Days}}
+	for _, c := range components {
+		value, remainder, ok := durationComponent(date, c.designator)
+		if !ok {
+			return fail()
+		}
+		*c.value, date = value, remainder
+	}
+	if date != "" {
+		return fail()
+	}
+
+	var hours, minutes int
+	var ok bool
+	if hours, clock, ok = durationComponent(clock, "H"); !ok {
+		return fail()
+	}
+	if minutes, clock, ok = durationComponent(clock, "M"); !ok {
+		return fail()
+	}
+	seconds, ok := durationSeconds(clock)
+	if !ok {
+		return fail()
+	}
+	if hours > math.MaxInt64/int(time.Hour) || minutes > math.MaxInt64/int(time.Minute) {
+		return fail()
+	}
+	d.Time = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	if d.Time < 0 || d.Time > math.MaxInt64-seconds {
+		return fail()
+	}
+	d.Time += seconds
+	if d.IsZero() {
+		d.Negative = false
+	}
+	return d, nil
+}
+
+// durationComponent reads the number followed by the designator, if the text starts with such component.
+func durationComponent(text, designator string) (int, string, bool) {
+	digits := strings.TrimLeft(text, "0123456789")
+	if !strings.HasPrefix(digits, designator) || len(digits) == len(text) {
+		return 0, text, true
+	}
+	value, err := strconv.Atoi(text[:len(text)-len(digits)])
+	if err != nil {
+		return 0, text, false
+	}
+	return value, strings.TrimPrefix(digits, designator), true
+}
+
+func durationSeconds(text string) (time.Duration, bool) {
+	if text == "" {
+		return 0, true
+	}
+	number, found := strings.CutSuffix(text, "S")
+	if !found {
+		return 0, false
+	}
+	whole, fraction, fractionGiven := strings.Cut(number, ".")
+	if whole == "" || strings.Trim(whole, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" ||
+		(fractionGiven && fraction == "") {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || seconds > math.MaxInt64/int64(time.Second) {
+		return 0, false
+	}
+	nanos, _ := strconv.Atoi((fraction + "000000000")[:9])
+	return time.Duration(seconds)*time.Second + time.Duration(nanos), true
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return (a - b + 1) / b
+	}
+	return a / b
+}
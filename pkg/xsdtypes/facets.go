@@ -0,0 +1,247 @@
+package xsdtypes
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Facets constrain values of simple type. Generated code declares Facets of each simple type restricting
+// its base type. Patterns are golang regular expressions translated from XSD by the generator, all the other
+// facets are given by their XSD lexical representation. Empty string denotes absent facet.
+type Facets struct {
+	Type           string // XSD name of the simple type
+	Patterns       []*regexp.Regexp
+	Enumeration    []string
+	Length         string
+	MinLength      string
+	MaxLength      string
+	MinInclusive   string
+	MaxInclusive   string
+	MinExclusive   string
+	MaxExclusive   string
+	TotalDigits    string
+	FractionDigits string
+	WhiteSpace     string // replace or collapse
+}
+
+// FacetError is returned when value violates facet of its type.
+type FacetError struct {
+	Type  string
+	Facet string
+	Value string
+	Limit string
+}
+
+func (e *FacetError) Error() string {
+	if e.Limit == "" {
+		return fmt.Sprintf("xsdtypes: value '%s' violates %s facet of %s", e.Value, e.Facet, e.Type)
+	}
+	return fmt.Sprintf("xsdtypes: value '%s' violates %s facet '%s' of %s", e.Value, e.Facet, e.Limit, e.Type)
+}
+
+// MustCompilePatterns compiles regular expressions of xsd:pattern facets as translated by the generator.
+func MustCompilePatterns(exprs ...string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(exprs))
+	for idx, expr := range exprs {
+		res[idx] = regexp.MustCompile(expr)
+	}
+	return res
+}
+
+// Validate checks the value against the facets. List values are measured in items, binary data in octets, other
+// values in characters.
+func (f *Facets) Validate(value any) error {
+	lexical, err := MarshalText(value)
+	if err != nil {
+		return err
+	}
+	lexical = NormalizeWhiteSpace(lexical, f.WhiteSpace)
+	fail := func(facet, limit string) error {
+		return &FacetError{Type: f.Type, Facet: facet, Value: lexical, Limit: limit}
+	}
+
+	for _, pattern := range f.Patterns {
+		if !pattern.MatchString(lexical) {
+			return fail("pattern", pattern.String())
+		}
+	}
+	if len(f.Enumeration) != 0 && !enumerationContains(f.Enumeration, value, lexical) {
+		return fail("enumeration", "")
+	}
+
+	length := utf8.RuneCountInString(lexical)
+	if m, ok := value.(measured); ok {
+		length = m.length()
+	} else if v := reflect.ValueOf(value); v.Kind() == reflect.Slice {
+		length = v.Len()
+	}
+	for _, limit := range []struct {
+		facet string
+		value string
+		ok    func(int) bool
+	}{
+		{"length", f.Length, func(n int) bool { return length == n }},
+		{"minLength", f.MinLength, func(n int) bool { return length >= n }},
+		{"maxLength", f.MaxLength, func(n int) bool { return length <= n }},
+	} {
+		if limit.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(limit.value)
+		if err != nil {
+			return fmt.Errorf("xsdtypes: malformed %s facet of %s: %w", limit.facet, f.Type, err)
+		}
+		if !limit.ok(n) {
+			return fail(limit.facet, limit.value)
+		}
+	}
+
+	for _, bound := range []struct {
+		facet string
+		value string
+		ok    func(int) bool
+	}{
+		{"minInclusive", f.MinInclusive, func(c int) bool { return c >= 0 }},
+		{"maxInclusive", f.MaxInclusive, func(c int) bool { return c <= 0 }},
+		{"minExclusive", f.MinExclusive, func(c int) bool { return c > 0 }},
+		{"maxExclusive", f.MaxExclusive, func(c int) bool { return c < 0 }},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		c, comparable := compare(value, lexical, bound.value)
+		if !comparable || !bound.ok(c) {
+			return fail(bound.facet, bound.value)
+		}
+	}
+
+	if f.TotalDigits != "" || f.FractionDigits != "" {
+		total, fraction, ok := countDigits(lexical)
+		if !ok {
+			return fail("totalDigits", f.TotalDigits)
+		}
+		if n, err := strconv.Atoi(f.TotalDigits); err == nil && total > n {
+			return fail("totalDigits", f.TotalDigits)
+		}
+		if n, err := strconv.Atoi(f.FractionDigits); err == nil && fraction > n {
+			return fail("fractionDigits", f.FractionDigits)
+		}
+	}
+	return nil
+}
+
+// NormalizeWhiteSpace applies whiteSpace facet (replace or collapse) to the lexical representation of value.
+func NormalizeWhiteSpace(text, whiteSpace string) string {
+	switch whiteSpace {
+	case "replace":
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(xmlWhitespace, r) {
+				return ' '
+			}
+			return r
+		}, text)
+	case "collapse":
+		return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+			return strings.ContainsRune(xmlWhitespace, r)
+		}), " ")
+	}
+	return text
+}
+
+// enumerationContains compares values in the value space of their type, so that for instance 1.0 equals 1.
+func enumerationContains(enumeration []string, value any, lexical string) bool {
+	for _, enum := range enumeration {
+		if enum == lexical {
+			return true
+		}
+		if o, ok := value.(ordered); ok {
+			if c, comparable := o.compareText(enum); comparable && c == 0 {
+				return true
+			}
+			continue
+		}
+		if value == nil || reflect.TypeOf(value).Kind() == reflect.String {
+			continue
+		}
+		parsed := reflect.New(reflect.TypeOf(value))
+		if UnmarshalText(parsed.Interface(), enum) == nil && reflect.DeepEqual(parsed.Elem().Interface(), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// measured is implemented by values not measured in characters, such as binary data.
+type measured interface {
+	length() int
+}
+
+// ordered is implemented by values of types with their own order, such as dates, times and durations.
+type ordered interface {
+	compareText(text string) (int, bool)
+}
+
+func compare(value any, lexical, bound string) (int, bool) {
+	if o, ok := value.(ordered); ok {
+		return o.compareText(bound)
+	}
+	return compareValues(lexical, bound)
+}
+
+// compareValues compares numbers numerically, and other values (dates and times) by their lexical form.
+func compareValues(lexical, bound string) (int, bool) {
+	a, aOk := parseNumber(lexical)
+	b, bOk := parseNumber(bound)
+	if aOk && bOk {
+		if a == nil || b == nil {
+			// NaN is not comparable
+			return 0, false
+		}
+		return a.Cmp(b), true
+	}
+	if aOk != bOk {
+		return 0, false
+	}
+	return strings.Compare(lexical, bound), true
+}
+
+// parseNumber parses XSD decimal, integer, float or double. Nil is returned for NaN.
+func parseNumber(text string) (*big.Float, bool) {
+	switch text {
+	case "INF", "+INF":
+		return new(big.Float).SetInf(false), true
+	case "-INF":
+		return new(big.Float).SetInf(true), true
+	case "NaN":
+		return nil, true
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Float).SetPrec(256).SetRat(r), true
+}
+
+// countDigits returns number of significant digits of decimal number, and number of its fraction digits.
+func countDigits(text string) (int, int, bool) {
+	r, ok := new(big.Rat).SetString(text)
+	if !ok || strings.Contains(text, "/") {
+		return 0, 0, false
+	}
+	fraction := 0
+	ten := big.NewRat(10, 1)
+	for !r.IsInt() {
+		r.Mul(r, ten)
+		fraction++
+	}
+	digits := strings.TrimLeft(new(big.Int).Abs(r.Num()).String(), "0")
+	if len(digits) < fraction {
+		return fraction, fraction, true
+	}
+	return max(len(digits), 1), fraction, true
+}
@@ -0,0 +1,473 @@
+package xsdtypes
+
+import (
+	"encoding"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// IdentityConstraint is xsd:key, xsd:keyref or xsd:unique declared by the schema. Selector and fields are given
+// by the restricted XPath subset of XSD (https://www.w3.org/TR/xmlschema-1/#c-selector-xpath). Namespace
+// prefixes of the name tests are ignored, elements and attributes are matched by their local names.
+type IdentityConstraint struct {
+	Element  string // local name of the element declaring the constraint, each of its occurrences is a scope
+	Kind     string // key, keyref or unique
+	Name     string
+	Refer    string // name of the key or unique referenced by keyref
+	Selector string
+	Fields   []string
+	// Local names of the elements held by struct fields tagged xml:",any", by golang type and field name
+	// (e.g. "ProductType.Name"), as these cannot be told from the values of non-struct types
+	AnyElements map[string]string
+}
+
+var (
+	ErrDuplicateKey      = errors.New("duplicate value")
+	ErrDanglingKeyref    = errors.New("dangling reference")
+	ErrMissingKeyField   = errors.New("missing field")
+	ErrAmbiguousKeyField = errors.New("ambiguous field")
+)
+
+// IdentityConstraintError locates element violating identity constraint.
+type IdentityConstraintError struct {
+	Path       string // XPath-like location of the offending element (items are indexed from 1)
+	Kind       string
+	Constraint string
+	Value      string // offending value, or XPath of the missing field
+	Err        error
+}
+
+func (e *IdentityConstraintError) Error() string {
+	return fmt.Sprintf("%s: %v '%s' of %s '%s'", e.Path, e.Err, e.Value, e.Kind, e.Constraint)
+}
+
+func (e *IdentityConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateIdentityConstraints walks the decoded document and checks the identity constraints within each of
+// their scopes. Keyrefs are resolved against keys declared by the same element or its descendants, as given by
+// the node tables of XSD (https://www.w3.org/TR/xmlschema-1/#cIdentity-constraint_Definitions). All the
+// violations found are reported.
+func ValidateIdentityConstraints(document any, constraints []IdentityConstraint) error {
+	if document == nil {
+		return nil
+	}
+	w := identityWalker{byElement: map[string][]*compiledConstraint{}}
+	anyElements := map[string]string{}
+	for idx := range constraints {
+		c, err := compileIdentityConstraint(&constraints[idx])
+		if err != nil {
+			return err
+		}
+		w.byElement[c.Element] = append(w.byElement[c.Element], c)
+		for field, name := range c.AnyElements {
+			anyElements[field] = name
+		}
+	}
+
+	// Copy the document, so that its fields are addressable
+	root := reflect.New(reflect.TypeOf(document))
+	root.Elem().Set(reflect.ValueOf(document))
+	v := derefNode(root)
+	if !v.IsValid() {
+		return nil
+	}
+	name := nodeName(v, "")
+	w.walk(identityNode{name: name, value: v, path: "/" + name, anyElements: anyElements})
+	return errors.Join(w.errs...)
+}
+
+type compiledConstraint struct {
+	*IdentityConstraint
+	selector []identityPath
+	fields   [][]identityPath
+}
+
+func compileIdentityConstraint(c *IdentityConstraint) (*compiledConstraint, error) {
+	res := &compiledConstraint{IdentityConstraint: c}
+	var err error
+	res.selector, err = parseIdentityXPath(c.Selector, false)
+	if err != nil {
+		return nil, fmt.Errorf("xsdtypes: malformed selector of %s '%s': %w", c.Kind, c.Name, err)
+	}
+	for _, field := range c.Fields {
+		paths, err := parseIdentityXPath(field, true)
+		if err != nil {
+			return nil, fmt.Errorf("xsdtypes: malformed field of %s '%s': %w", c.Kind, c.Name, err)
+		}
+		res.fields = append(res.fields, paths)
+	}
+	return res, nil
+}
+
+// identityPath is single alternative of selector or field XPath.
+type identityPath struct {
+	descendants bool     // path starts with .//
+	steps       []string // local names of child elements, * matches any
+	attribute   string   // trailing attribute step of field, empty if there is none
+}
+
+func parseIdentityXPath(expr string, field bool) ([]identityPath, error) {
+	res := []identityPath{}
+	for _, alternative := range strings.Split(expr, "|") {
+		alternative = strings.TrimSpace(alternative)
+		p := identityPath{}
+		if strings.HasPrefix(alternative, ".//") {
+			p.descendants = true
+			alternative = strings.TrimPrefix(alternative, ".//")
+		}
+		steps := strings.Split(alternative, "/")
+		for idx, step := range steps {
+			step = strings.TrimSpace(step)
+			step = strings.TrimPrefix(step, "child::")
+			if strings.HasPrefix(step, "attribute::") {
+				step = "@" + strings.TrimPrefix(step, "attribute::")
+			}
+			switch {
+			case step == "":
+				return nil, fmt.Errorf("empty step in '%s'", expr)
+			case step == ".":
+			case strings.HasPrefix(step, "@"):
+				if !field || idx != len(steps)-1 {
+					return nil, fmt.Errorf("unexpected attribute step in '%s'", expr)
+				}
+				p.attribute = localName(strings.TrimPrefix(step, "@"))
+			default:
+				p.steps = append(p.steps, localName(step))
+			}
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+func localName(qname string) string {
+	if pos := strings.Index(qname, ":"); pos != -1 {
+		return qname[pos+1:]
+	}
+	return qname
+}
+
+type identityWalker struct {
+	byElement map[string][]*compiledConstraint
+	errs      []error
+}
+
+// keyTable maps values of key or unique to paths of the elements holding them.
+type keyTable map[string]string
+
+// walk checks the constraints declared by the node and its descendants. It returns the key tables of the node,
+// which hold values of the keys and uniques declared by the node itself and by its descendants.
+func (w *identityWalker) walk(n identityNode) map[string]keyTable {
+	tables := map[string]keyTable{}
+	for _, child := range n.children() {
+		for name, table := range w.walk(child) {
+			tables[name] = table.merge(tables[name])
+		}
+	}
+	declared := w.byElement[n.name]
+	for _, c := range declared {
+		if c.Kind != "keyref" {
+			tables[c.Name] = w.collect(n, c).merge(tables[c.Name])
+		}
+	}
+	for _, c := range declared {
+		if c.Kind == "keyref" {
+			w.resolve(n, c, tables[c.Refer])
+		}
+	}
+	return tables
+}
+
+// merge returns table holding entries of both tables, the entries of this one take precedence.
+func (t keyTable) merge(other keyTable) keyTable {
+	res := make(keyTable, len(t)+len(other))
+	for key, path := range other {
+		res[key] = path
+	}
+	for key, path := range t {
+		res[key] = path
+	}
+	return res
+}
+
+func (w *identityWalker) collect(scope identityNode, c *compiledConstraint) keyTable {
+	table := keyTable{}
+	for _, n := range selectNodes(scope, c.selector) {
+		key, display, ok := w.evaluateFields(n, c)
+		if !ok {
+			continue
+		}
+		if _, found := table[key]; found {
+			w.fail(n, c, display, ErrDuplicateKey)
+			continue
+		}
+		table[key] = n.path
+	}
+	return table
+}
+
+func (w *identityWalker) resolve(scope identityNode, c *compiledConstraint, table keyTable) {
+	if table == nil {
+		// Referenced constraint is not in scope, it cannot be checked
+		return
+	}
+	for _, n := range selectNodes(scope, c.selector) {
+		key, display, ok := w.evaluateFields(n, c)
+		if !ok {
+			continue
+		}
+		if _, found := table[key]; !found {
+			w.fail(n, c, display, ErrDanglingKeyref)
+		}
+	}
+}
+
+// evaluateFields returns the key value of the selected node. Nodes missing any of the fields are not part of
+// the constraint, unless it is a key.
+func (w *identityWalker) evaluateFields(n identityNode, c *compiledConstraint) (string, string, bool) {
+	values := make([]string, len(c.fields))
+	for idx, field := range c.fields {
+		found := fieldValues(n, field)
+		switch {
+		case len(found) > 1:
+			w.fail(n, c, c.Fields[idx], ErrAmbiguousKeyField)
+			return "", "", false
+		case len(found) == 0:
+			if c.Kind == "key" {
+				w.fail(n, c, c.Fields[idx], ErrMissingKeyField)
+			}
+			return "", "", false
+		}
+		values[idx] = found[0]
+	}
+	return strings.Join(values, "\x00"), strings.Join(values, ", "), true
+}
+
+func (w *identityWalker) fail(n identityNode, c *compiledConstraint, value string, err error) {
+	w.errs = append(w.errs, &IdentityConstraintError{
+		Path: n.path, Kind: c.Kind, Constraint: c.Name, Value: value, Err: err,
+	})
+}
+
+func selectNodes(context identityNode, paths []identityPath) []identityNode {
+	res := []identityNode{}
+	for _, p := range paths {
+		nodes := []identityNode{context}
+		if p.descendants {
+			nodes = context.descendantsOrSelf()
+		}
+		for _, step := range p.steps {
+			next := []identityNode{}
+			for _, n := range nodes {
+				for _, child := range n.children() {
+					if step == "*" || step == child.name {
+						next = append(next, child)
+					}
+				}
+			}
+			nodes = next
+		}
+		res = append(res, nodes...)
+	}
+	return res
+}
+
+func fieldValues(n identityNode, paths []identityPath) []string {
+	res := []string{}
+	for _, p := range paths {
+		attribute := p.attribute
+		p.attribute = ""
+		for _, selected := range selectNodes(n, []identityPath{p}) {
+			var value string
+			var ok bool
+			if attribute != "" {
+				value, ok = selected.attr(attribute)
+			} else {
+				value, ok = selected.text()
+			}
+			if ok {
+				res = append(res, value)
+			}
+		}
+	}
+	return res
+}
+
+// identityNode is element of the decoded document.
+type identityNode struct {
+	name        string
+	value       reflect.Value
+	path        string
+	anyElements map[string]string
+}
+
+func (n identityNode) descendantsOrSelf() []identityNode {
+	res := []identityNode{n}
+	for _, child := range n.children() {
+		res = append(res, child.descendantsOrSelf()...)
+	}
+	return res
+}
+
+func (n identityNode) children() []identityNode {
+	v := n.value
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	res := []identityNode{}
+	counts := map[string]int{}
+	for idx := 0; idx < v.NumField(); idx++ {
+		field := v.Type().Field(idx)
+		name, flags := parseXmlTag(field)
+		if name == "-" || flags["attr"] || flags["chardata"] || flags["cdata"] || flags["innerxml"] ||
+			flags["comment"] {
+			continue
+		}
+		items := []reflect.Value{v.Field(idx)}
+		if fv := v.Field(idx); fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			items = items[:0]
+			for item := 0; item < fv.Len(); item++ {
+				items = append(items, fv.Index(item))
+			}
+		}
+		for _, item := range items {
+			if flags["omitempty"] && item.IsZero() {
+				continue
+			}
+			item = derefNode(item)
+			if !item.IsValid() {
+				continue
+			}
+			itemName := nodeName(item, name)
+			if itemName == "" && flags["any"] {
+				itemName = n.anyElements[v.Type().Name()+"."+field.Name]
+			}
+			if itemName == "" {
+				continue
+			}
+			counts[itemName]++
+			res = append(res, identityNode{
+				name:        itemName,
+				value:       item,
+				path:        fmt.Sprintf("%s/%s[%d]", n.path, itemName, counts[itemName]),
+				anyElements: n.anyElements,
+			})
+		}
+	}
+	return res
+}
+
+func (n identityNode) attr(name string) (string, bool) {
+	v := n.value
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	for idx := 0; idx < v.NumField(); idx++ {
+		tagName, flags := parseXmlTag(v.Type().Field(idx))
+		if !flags["attr"] {
+			continue
+		}
+		fv := v.Field(idx)
+		if flags["any"] {
+			if attrs, ok := fv.Interface().([]xml.Attr); ok {
+				for _, attr := range attrs {
+					if attr.Name.Local == name {
+						return strings.TrimSpace(attr.Value), true
+					}
+				}
+			}
+			continue
+		}
+		if tagName != name || (flags["omitempty"] && fv.IsZero()) {
+			continue
+		}
+		return textOf(derefNode(fv))
+	}
+	return "", false
+}
+
+func (n identityNode) text() (string, bool) {
+	v := n.value
+	if v.Kind() != reflect.Struct {
+		return textOf(v)
+	}
+	if _, ok := v.Interface().(encoding.TextMarshaler); ok {
+		return textOf(v)
+	}
+	for idx := 0; idx < v.NumField(); idx++ {
+		if _, flags := parseXmlTag(v.Type().Field(idx)); flags["chardata"] {
+			return textOf(derefNode(v.Field(idx)))
+		}
+	}
+	return "", false
+}
+
+func textOf(v reflect.Value) (string, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return "", false
+	}
+	text, err := MarshalText(v.Interface())
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(text), true
+}
+
+// derefNode looks through pointers, interfaces and nillable wrappers, returning invalid value if there is
+// nothing to look at.
+func derefNode(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return v
+	}
+	return dereference(v)
+}
+
+// nodeName returns local name of element held by the value, given by its XMLName field if there is one.
+func nodeName(v reflect.Value, tagName string) string {
+	if v.Kind() == reflect.Struct {
+		if field, ok := v.Type().FieldByName("XMLName"); ok && field.Type == reflect.TypeOf(xml.Name{}) {
+			if name := v.FieldByIndex(field.Index).Interface().(xml.Name); name.Local != "" {
+				return name.Local
+			}
+			if name := strings.Split(field.Tag.Get("xml"), ",")[0]; name != "" {
+				return name[strings.LastIndex(name, " ")+1:]
+			}
+		}
+	}
+	return tagName
+}
+
+// parseXmlTag returns local name and flags given by xml tag of the struct field.
+func parseXmlTag(field reflect.StructField) (string, map[string]bool) {
+	if !field.IsExported() || field.Name == "XMLName" {
+		return "-", nil
+	}
+	tag, found := field.Tag.Lookup("xml")
+	if !found {
+		return field.Name, map[string]bool{}
+	}
+	parts := strings.Split(tag, ",")
+	flags := map[string]bool{}
+	for _, flag := range parts[1:] {
+		flags[flag] = true
+	}
+	name := parts[0]
+	if pos := strings.LastIndex(name, " "); pos != -1 {
+		name = name[pos+1:]
+	}
+	if name == "" && !flags["any"] {
+		name = field.Name
+	}
+	return name, flags
+}
@@ -0,0 +1,41 @@
+package xsdtypes
+
+import (
+	"strings"
+)
+
+// Characters considered whitespace by XML specification.
+const xmlWhitespace = " \t\r\n"
+
+// SplitList splits value of xsd:list type into its items.
+func SplitList(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(xmlWhitespace, r)
+	})
+}
+
+// MarshalList joins items of xsd:list type into single whitespace separated value.
+func MarshalList[L ~[]T, T any](list L) ([]byte, error) {
+	items := make([]string, len(list))
+	for idx, item := range list {
+		text, err := MarshalText(item)
+		if err != nil {
+			return nil, err
+		}
+		items[idx] = text
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+// UnmarshalList parses whitespace separated value of xsd:list type.
+func UnmarshalList[L ~[]T, T any](text []byte, list *L) error {
+	fields := SplitList(string(text))
+	result := make(L, len(fields))
+	for idx, field := range fields {
+		if err := UnmarshalText(&result[idx], field); err != nil {
+			return err
+		}
+	}
+	*list = result
+	return nil
+}
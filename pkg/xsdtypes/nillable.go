@@ -0,0 +1,70 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Nillable holds value of XML element declared as nillable="true". Nil reports whether the element
+// was present in the document with xsi:nil="true" attribute, in which case Value is left unset.
+type Nillable[T any] struct {
+	Value T
+	Nil   bool
+}
+
+func (n *Nillable[T]) value() any {
+	return &n.Value
+}
+
+func (n *Nillable[T]) isNil() bool {
+	return n.Nil
+}
+
+// UnmarshalXML decodes the element into Value, unless it is marked by xsi:nil.
+func (n *Nillable[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*n = Nillable[T]{}
+	if IsNil(start) {
+		n.Nil = true
+		return d.Skip()
+	}
+	return d.DecodeElement(&n.Value, &start)
+}
+
+// MarshalXML encodes Value, or empty element with xsi:nil="true" attribute when Nil is set.
+func (n Nillable[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !n.Nil {
+		return e.EncodeElement(n.Value, start)
+	}
+	return EncodeNil(e, start)
+}
+
+// EncodeNil encodes empty element marked by xsi:nil="true" attribute. Generated MarshalXML methods of nillable
+// top-level elements call it, when their Nil field is set.
+func EncodeNil(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: XsiNamespace},
+		xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"})
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// IsNil reports whether the element carries xsi:nil="true" attribute.
+func IsNil(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "nil" && (attr.Name.Space == XsiNamespace || attr.Name.Space == "xsi") {
+			value := strings.TrimSpace(attr.Value)
+			return value == "true" || value == "1"
+		}
+	}
+	return false
+}
+
+// Validate validates the value, unless it is nil.
+func (n Nillable[T]) Validate() error {
+	if n.Nil {
+		return nil
+	}
+	return Validate("", n.Value)
+}
@@ -0,0 +1,260 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// NumberError is returned for malformed lexical representation of decimal or integer.
+type NumberError struct {
+	Type  string
+	Value string
+}
+
+func (e *NumberError) Error() string {
+	return fmt.Sprintf("xsdtypes: invalid %s value '%s'", e.Type, e.Value)
+}
+
+// Decimal holds value of xsd:decimal in its lexical representation, so that no precision is lost and the number
+// of fraction digits (e.g. of monetary amounts) is kept. Empty Decimal denotes absent value. Arithmetic helpers
+// treat malformed values, which may only be obtained by conversion from string, as zero.
+type Decimal string
+
+// ParseDecimal checks lexical representation of xsd:decimal.
+func ParseDecimal(text string) (Decimal, error) {
+	if _, _, ok := decimalParts(text); !ok {
+		return "", &NumberError{Type: "decimal", Value: text}
+	}
+	return Decimal(text), nil
+}
+
+// DecimalFromRat returns the number rounded to the given number of fraction digits, halves away from zero.
+func DecimalFromRat(r *big.Rat, fractionDigits int) Decimal {
+	return Decimal(r.FloatString(max(fractionDigits, 0)))
+}
+
+// decimalParts returns the value of the decimal and the number of its fraction digits.
+func decimalParts(text string) (*big.Rat, int, bool) {
+	digits := strings.TrimLeft(text, "+-")
+	if len(text)-len(digits) > 1 {
+		return nil, 0, false
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole+fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return nil, 0, false
+	}
+	unscaled, _ := new(big.Int).SetString("0"+whole+fraction, 10)
+	if strings.HasPrefix(text, "-") {
+		unscaled.Neg(unscaled)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fraction))), nil)
+	return new(big.Rat).SetFrac(unscaled, scale), len(fraction), true
+}
+
+// Rat returns the value of the decimal.
+func (d Decimal) Rat() *big.Rat {
+	r, _, ok := decimalParts(string(d))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+func (d Decimal) fractionDigits() int {
+	_, fraction, _ := decimalParts(string(d))
+	return fraction
+}
+
+// Digits returns the number of significant digits and the number of fraction digits, as constrained by
+// totalDigits and fractionDigits facets.
+func (d Decimal) Digits() (int, int) {
+	total, fraction, _ := countDigits(d.Rat().FloatString(d.fractionDigits()))
+	return total, fraction
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// Add returns the exact sum, with as many fraction digits as the operand having more of them.
+func (d Decimal) Add(other Decimal) Decimal {
+	return DecimalFromRat(new(big.Rat).Add(d.Rat(), other.Rat()), max(d.fractionDigits(), other.fractionDigits()))
+}
+
+// Sub returns the exact difference, with as many fraction digits as the operand having more of them.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return DecimalFromRat(new(big.Rat).Sub(d.Rat(), other.Rat()), max(d.fractionDigits(), other.fractionDigits()))
+}
+
+// Mul returns the exact product.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return DecimalFromRat(new(big.Rat).Mul(d.Rat(), other.Rat()), d.fractionDigits()+other.fractionDigits())
+}
+
+// Round returns the decimal rounded to the given number of fraction digits, halves away from zero.
+func (d Decimal) Round(fractionDigits int) Decimal {
+	return DecimalFromRat(d.Rat(), fractionDigits)
+}
+
+func (d Decimal) String() string {
+	return string(d)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	lexical := strings.Trim(string(text), xmlWhitespace)
+	if lexical == "" {
+		*d = ""
+		return nil
+	}
+	res, err := ParseDecimal(lexical)
+	if err != nil {
+		return err
+	}
+	*d = res
+	return nil
+}
+
+// MarshalXMLAttr leaves out the attribute of empty value, which denotes absent optional attribute.
+func (d Decimal) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if d == "" {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: string(d)}, nil
+}
+
+func (d Decimal) compareText(text string) (int, bool) {
+	r, _, ok := decimalParts(text)
+	if !ok {
+		return 0, false
+	}
+	return d.Rat().Cmp(r), true
+}
+
+// Integer holds value of xsd:integer, which is not bounded. Zero value denotes absent value, unlike the Integer
+// holding zero.
+type Integer struct {
+	value *big.Int
+}
+
+// NonNegativeInteger holds value of xsd:nonNegativeInteger.
+type NonNegativeInteger struct {
+	Integer
+}
+
+// PositiveInteger holds value of xsd:positiveInteger.
+type PositiveInteger struct {
+	Integer
+}
+
+// NonPositiveInteger holds value of xsd:nonPositiveInteger.
+type NonPositiveInteger struct {
+	Integer
+}
+
+// NegativeInteger holds value of xsd:negativeInteger.
+type NegativeInteger struct {
+	Integer
+}
+
+func NewInteger(x *big.Int) Integer {
+	return Integer{value: new(big.Int).Set(x)}
+}
+
+func IntegerFromInt64(x int64) Integer {
+	return Integer{value: big.NewInt(x)}
+}
+
+// BigInt returns copy of the value, zero for absent value.
+func (i Integer) BigInt() *big.Int {
+	if i.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(i.value)
+}
+
+// IsAbsent reports whether the Integer holds no value.
+func (i Integer) IsAbsent() bool {
+	return i.value == nil
+}
+
+func (i Integer) Cmp(other Integer) int {
+	return i.BigInt().Cmp(other.BigInt())
+}
+
+func (i Integer) String() string {
+	if i.value == nil {
+		return ""
+	}
+	return i.value.String()
+}
+
+func (i Integer) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+func (i *Integer) UnmarshalText(text []byte) error {
+	return i.unmarshal(text, "integer", func(int) bool { return true })
+}
+
+func (i *NonNegativeInteger) UnmarshalText(text []byte) error {
+	return i.unmarshal(text, "nonNegativeInteger", func(sign int) bool { return sign >= 0 })
+}
+
+func (i *PositiveInteger) UnmarshalText(text []byte) error {
+	return i.unmarshal(text, "positiveInteger", func(sign int) bool { return sign > 0 })
+}
+
+func (i *NonPositiveInteger) UnmarshalText(text []byte) error {
+	return i.unmarshal(text, "nonPositiveInteger", func(sign int) bool { return sign <= 0 })
+}
+
+func (i *NegativeInteger) UnmarshalText(text []byte) error {
+	return i.unmarshal(text, "negativeInteger", func(sign int) bool { return sign < 0 })
+}
+
+func (i *Integer) unmarshal(text []byte, typeName string, signOk func(int) bool) error {
+	lexical := strings.Trim(string(text), xmlWhitespace)
+	if lexical == "" {
+		*i = Integer{}
+		return nil
+	}
+	value, ok := parseInteger(lexical)
+	if !ok || !signOk(value.Sign()) {
+		return &NumberError{Type: typeName, Value: lexical}
+	}
+	i.value = value
+	return nil
+}
+
+func parseInteger(text string) (*big.Int, bool) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(text, "+"), "-")
+	if len(text)-len(digits) > 1 || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return nil, false
+	}
+	return new(big.Int).SetString(text, 10)
+}
+
+// MarshalXMLAttr leaves out the attribute of absent value.
+func (i Integer) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if i.value == nil {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: i.value.String()}, nil
+}
+
+func (i Integer) compareText(text string) (int, bool) {
+	if i.value == nil {
+		return 0, false
+	}
+	other, ok := parseInteger(text)
+	if !ok {
+		return 0, false
+	}
+	return i.value.Cmp(other), true
+}
@@ -0,0 +1,246 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// QName holds value of xsd:QName, with its prefix resolved to the namespace declared in scope. Zero value
+// denotes absent value.
+//
+// encoding/xml does not expose namespace declarations in scope, so the prefix is resolved using xmlns attributes
+// of the element holding the name only, unless the document is decoded by NamespaceDecoder. Prefix, which is not
+// declared there, is reported by ErrQNameUnresolved. Names without prefix are in the default namespace declared
+// by the element, or in no namespace. Values of attributes are resolved by the generated UnmarshalXML methods of
+// the enclosing element, as UnmarshalXMLAttr is given the attribute alone.
+type QName struct {
+	xml.Name
+	Prefix string // prefix the name was given with, reused when marshalling it
+}
+
+// QNameError is returned for malformed lexical representation of xsd:QName.
+type QNameError struct {
+	Value string
+}
+
+func (e *QNameError) Error() string {
+	return fmt.Sprintf("xsdtypes: invalid QName value '%s'", e.Value)
+}
+
+var (
+	// ErrQNamePrefix is returned when marshalling namespaced QName, which has no prefix assigned.
+	ErrQNamePrefix = errors.New("xsdtypes: QName in namespace has no prefix to be marshalled with")
+	// ErrQNameUnresolved is returned when decoding QName, which prefix is not declared by the element holding it.
+	// Prefixes declared by the enclosing elements are resolved when the document is decoded by NamespaceDecoder.
+	ErrQNameUnresolved = errors.New("xsdtypes: QName prefix is not declared")
+)
+
+// String returns the name as given in XML, qualified by its prefix.
+func (q QName) String() string {
+	if q.Prefix == "" {
+		return q.Local
+	}
+	return q.Prefix + ":" + q.Local
+}
+
+func (q *QName) parse(text string) error {
+	lexical := strings.Trim(text, xmlWhitespace)
+	if lexical == "" {
+		*q = QName{}
+		return nil
+	}
+	prefix, local, found := strings.Cut(lexical, ":")
+	if !found {
+		prefix, local = "", prefix
+	}
+	if local == "" || (found && prefix == "") || strings.ContainsAny(local, ":"+xmlWhitespace) ||
+		strings.ContainsAny(prefix, xmlWhitespace) {
+		return &QNameError{Value: lexical}
+	}
+	*q = QName{Name: xml.Name{Local: local}, Prefix: prefix}
+	return nil
+}
+
+// resolve looks up namespace of the prefix among the declarations of the element.
+func (q *QName) resolve(el xml.StartElement) error {
+	if q.Prefix == "xml" {
+		q.Space = xmlNamespace
+		return nil
+	}
+	space, ok := lookupXmlns(el, q.Prefix)
+	if !ok && q.Prefix != "" {
+		return fmt.Errorf("%w: %s", ErrQNameUnresolved, q)
+	}
+	q.Space = space
+	return nil
+}
+
+func (q *QName) qname() *QName {
+	return q
+}
+
+func (q *QName) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	if err := q.parse(text); err != nil {
+		return err
+	}
+	return q.resolve(start)
+}
+
+// UnmarshalXMLAttr leaves the namespace of the name empty, ResolveQNames resolves it afterwards.
+func (q *QName) UnmarshalXMLAttr(attr xml.Attr) error {
+	return q.parse(attr.Value)
+}
+
+// MarshalXML declares the prefix of the name on the element holding it.
+func (q QName) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	DeclareQNames(&start, &q)
+	return e.EncodeElement(q.String(), start)
+}
+
+// MarshalXMLAttr leaves out the attribute of zero value. The prefix has to be declared by the enclosing element,
+// as done by DeclareQNames.
+func (q QName) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if q.Local == "" {
+		return xml.Attr{}, nil
+	}
+	if q.Space != "" && q.Prefix == "" {
+		return xml.Attr{}, fmt.Errorf("%w: {%s}%s", ErrQNamePrefix, q.Space, q.Local)
+	}
+	return xml.Attr{Name: name, Value: q.String()}, nil
+}
+
+// qnameHolder is implemented by pointers to QName and to the types embedding it.
+type qnameHolder interface {
+	qname() *QName
+}
+
+// ResolveQNames resolves prefixes of the names held by attributes of the element. Generated UnmarshalXML methods
+// call it with pointers to the attribute fields.
+func ResolveQNames(start xml.StartElement, names ...any) error {
+	var errs []error
+	for _, name := range names {
+		if holder, ok := name.(qnameHolder); ok {
+			errs = append(errs, holder.qname().resolve(start))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeclareQNames adds namespace declarations of the names to the start element. Names without prefix, or with
+// prefix bound to another namespace by the element, are given a fresh one. Generated MarshalXML methods call it
+// with pointers to the attribute fields of the copy being marshalled.
+func DeclareQNames(start *xml.StartElement, names ...any) {
+	for _, name := range names {
+		holder, ok := name.(qnameHolder)
+		if !ok {
+			continue
+		}
+		q := holder.qname()
+		if q.Space == "" || q.Local == "" {
+			continue
+		}
+		if q.Space == xmlNamespace {
+			q.Prefix = "xml"
+			continue
+		}
+		space, bound := declaredXmlns(*start, q.Prefix)
+		if bound && space == q.Space && q.Prefix != "" {
+			continue
+		}
+		if bound || q.Prefix == "" || q.Prefix == "xml" {
+			q.Prefix = freshPrefix(*start)
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + q.Prefix}, Value: q.Space})
+	}
+}
+
+// declaredXmlns looks up the prefix among declarations of the start element being marshalled, which may be
+// given either as decoded by encoding/xml or as SetXsiType and DeclareQNames add them.
+func declaredXmlns(start xml.StartElement, prefix string) (string, bool) {
+	if space, ok := lookupXmlns(start, prefix); ok {
+		return space, true
+	}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == "xmlns:"+prefix {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+func freshPrefix(start xml.StartElement) string {
+	for idx := 1; ; idx++ {
+		prefix := fmt.Sprintf("ns%d", idx)
+		if _, bound := declaredXmlns(start, prefix); !bound {
+			return prefix
+		}
+	}
+}
+
+// NamespaceDecoder returns decoder of the tokens read by d, which repeats the namespace declarations in scope
+// on each start element. This lets QName values and xsi:type attributes use prefixes declared by any of
+// the enclosing elements. Fields collecting any attributes receive the repeated declarations as well.
+func NamespaceDecoder(d *xml.Decoder) *xml.Decoder {
+	return xml.NewTokenDecoder(&namespaceReader{d: d})
+}
+
+type namespaceReader struct {
+	d      *xml.Decoder
+	scopes [][]xml.Attr // declarations in scope of each open element
+}
+
+func (r *namespaceReader) Token() (xml.Token, error) {
+	tok, err := r.d.RawToken()
+	if err != nil {
+		return nil, err
+	}
+	tok = xml.CopyToken(tok)
+	switch t := tok.(type) {
+	case xml.StartElement:
+		var inherited []xml.Attr
+		if len(r.scopes) != 0 {
+			inherited = r.scopes[len(r.scopes)-1]
+		}
+		scope := []xml.Attr{}
+		for _, attr := range t.Attr {
+			if isXmlns(attr) {
+				scope = append(scope, attr)
+			}
+		}
+		for _, attr := range inherited {
+			if !declares(scope, attr) {
+				scope = append(scope, attr)
+				t.Attr = append(t.Attr, attr)
+			}
+		}
+		r.scopes = append(r.scopes, scope)
+		return t, nil
+	case xml.EndElement:
+		if len(r.scopes) != 0 {
+			r.scopes = r.scopes[:len(r.scopes)-1]
+		}
+	}
+	return tok, nil
+}
+
+func isXmlns(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// declares reports whether the scope declares the same prefix (or the default namespace) as the attribute.
+func declares(scope []xml.Attr, attr xml.Attr) bool {
+	for _, decl := range scope {
+		if decl.Name == attr.Name {
+			return true
+		}
+	}
+	return false
+}
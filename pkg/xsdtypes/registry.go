@@ -0,0 +1,35 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+)
+
+// Registry maps XML names to factories of golang types implementing T.
+//
+// Generated code uses registries to instantiate concrete types, where XSD allows multiple types to appear
+// at single place of the document (for instance members of element substitution groups).
+type Registry[T any] map[xml.Name]func() T
+
+// Register adds factory for given XML name. Generated packages register their types from init functions.
+func (r Registry[T]) Register(name xml.Name, factory func() T) {
+	r[name] = factory
+}
+
+// New creates new instance of the type registered for given XML name.
+func (r Registry[T]) New(name xml.Name) (T, bool) {
+	factory, found := r[name]
+	if !found {
+		// Fallback for documents that do not qualify element names by namespace
+		for registered, f := range r {
+			if registered.Local == name.Local && (name.Space == "" || registered.Space == "") {
+				factory, found = f, true
+				break
+			}
+		}
+	}
+	if !found {
+		var zero T
+		return zero, false
+	}
+	return factory(), true
+}
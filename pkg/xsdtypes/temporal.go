@@ -0,0 +1,365 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Temporal holds value of XSD date or time type, given by the layout L. Time is the instant the lexical
+// representation denotes, NoTimezone reports that the representation has no timezone, in which case Time
+// is given in UTC. The fields absent from the representation are left at 1972-01-01T00:00:00 (e.g. for
+// xsd:time or xsd:gMonth). Zero value is treated as absent and marshalled as empty text.
+type Temporal[L temporalLayout] struct {
+	Time       time.Time
+	NoTimezone bool
+}
+
+type (
+	DateTime   = Temporal[dateTimeLayout]
+	Date       = Temporal[dateLayout]
+	Time       = Temporal[timeLayout]
+	GYear      = Temporal[gYearLayout]
+	GYearMonth = Temporal[gYearMonthLayout]
+	GMonthDay  = Temporal[gMonthDayLayout]
+	GDay       = Temporal[gDayLayout]
+	GMonth     = Temporal[gMonthLayout]
+)
+
+// layout gives the fields the lexical representation of the type consists of.
+type layout struct {
+	name                    string
+	year, month, day, clock bool
+}
+
+type temporalLayout interface {
+	layout() layout
+}
+
+type (
+	dateTimeLayout   struct{}
+	dateLayout       struct{}
+	timeLayout       struct{}
+	gYearLayout      struct{}
+	gYearMonthLayout struct{}
+	gMonthDayLayout  struct{}
+	gDayLayout       struct{}
+	gMonthLayout     struct{}
+)
+
+func (dateTimeLayout) layout() layout {
+	return layout{name: "dateTime", year: true, month: true, day: true, clock: true}
+}
+func (dateLayout) layout() layout       { return layout{name: "date", year: true, month: true, day: true} }
+func (timeLayout) layout() layout       { return layout{name: "time", clock: true} }
+func (gYearLayout) layout() layout      { return layout{name: "gYear", year: true} }
+func (gYearMonthLayout) layout() layout { return layout{name: "gYearMonth", year: true, month: true} }
+func (gMonthDayLayout) layout() layout  { return layout{name: "gMonthDay", month: true, day: true} }
+func (gDayLayout) layout() layout       { return layout{name: "gDay", day: true} }
+func (gMonthLayout) layout() layout     { return layout{name: "gMonth", month: true} }
+
+// referenceYear is a leap year, so that --02-29 is a valid gMonthDay.
+const referenceYear = 1972
+
+func (v Temporal[L]) layout() layout {
+	var l L
+	return l.layout()
+}
+
+func (v Temporal[L]) String() string {
+	return formatTemporal(v.layout(), v.Time, v.NoTimezone)
+}
+
+func (v Temporal[L]) MarshalText() ([]byte, error) {
+	if v.Time.IsZero() && !v.NoTimezone {
+		return []byte{}, nil
+	}
+	return []byte(v.String()), nil
+}
+
+func (v *Temporal[L]) UnmarshalText(text []byte) error {
+	lexical := strings.Trim(string(text), xmlWhitespace)
+	if lexical == "" {
+		*v = Temporal[L]{}
+		return nil
+	}
+	t, noTimezone, err := parseTemporal(v.layout(), lexical)
+	if err != nil {
+		return err
+	}
+	*v = Temporal[L]{Time: t, NoTimezone: noTimezone}
+	return nil
+}
+
+// MarshalXMLAttr leaves out the attribute of zero value, which denotes absent optional attribute.
+func (v Temporal[L]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	text, err := v.MarshalText()
+	if err != nil || len(text) == 0 {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
+
+// compareText compares the value with lexical representation of another value of the type. Values with and
+// without timezone are not comparable, unless these are more than 14 hours apart.
+func (v Temporal[L]) compareText(text string) (int, bool) {
+	t, noTimezone, err := parseTemporal(v.layout(), text)
+	if err != nil {
+		return 0, false
+	}
+	return compareTemporal(v.Time, v.NoTimezone, t, noTimezone)
+}
+
+func compareTemporal(a time.Time, aNoTimezone bool, b time.Time, bNoTimezone bool) (int, bool) {
+	if aNoTimezone == bNoTimezone {
+		return a.Compare(b), true
+	}
+	if aNoTimezone {
+		c, ok := compareTemporal(b, bNoTimezone, a, aNoTimezone)
+		return -c, ok
+	}
+	// The value without timezone may stand for any instant within 14 hours of its UTC reading
+	switch {
+	case a.Before(b.Add(-14 * time.Hour)):
+		return -1, true
+	case a.After(b.Add(14 * time.Hour)):
+		return 1, true
+	}
+	return 0, false
+}
+
+// TemporalError is returned for malformed lexical representation of date or time type.
+type TemporalError struct {
+	Type  string
+	Value string
+}
+
+func (e *TemporalError) Error() string {
+	return fmt.Sprintf("xsdtypes: invalid %s value '%s'", e.Type, e.Value)
+}
+
+// parseTemporal parses the lexical representation given by the layout. The year may be negative and have more
+// than four digits, fractional seconds are truncated to nanoseconds and 24:00:00 stands for the start of
+// the next day.
+func parseTemporal(l layout, text string) (time.Time, bool, error) {
+	fail := func() (time.Time, bool, error) {
+		return time.Time{}, false, &TemporalError{Type: l.name, Value: text}
+	}
+	s := &scanner{text: text}
+	year, month, day := referenceYear, 1, 1
+	hour, minute, second, nanos := 0, 0, 0, 0
+	var ok bool
+
+	switch {
+	case l.year:
+		if year, ok = s.year(); !ok {
+			return fail()
+		}
+	case l.month:
+		ok = s.literal("--")
+	case l.day:
+		ok = s.literal("---")
+	default:
+		ok = true
+	}
+	if !ok {
+		return fail()
+	}
+	if l.month {
+		if l.year && !s.literal("-") {
+			return fail()
+		}
+		if month, ok = s.digits(2); !ok || month < 1 || month > 12 {
+			return fail()
+		}
+	}
+	if l.day {
+		if l.month && !s.literal("-") {
+			return fail()
+		}
+		if day, ok = s.digits(2); !ok || day < 1 || day > daysIn(year, time.Month(month)) {
+			return fail()
+		}
+	}
+	if l.clock {
+		if l.year && !s.literal("T") {
+			return fail()
+		}
+		if hour, ok = s.digits(2); !ok || !s.literal(":") {
+			return fail()
+		}
+		if minute, ok = s.digits(2); !ok || minute > 59 || !s.literal(":") {
+			return fail()
+		}
+		if second, ok = s.digits(2); !ok || second > 59 {
+			return fail()
+		}
+		if s.literal(".") {
+			if nanos, ok = s.fraction(); !ok {
+				return fail()
+			}
+		}
+		if hour > 24 || (hour == 24 && (minute != 0 || second != 0 || nanos != 0)) {
+			return fail()
+		}
+	}
+
+	loc, noTimezone := time.UTC, true
+	if !s.done() {
+		if loc, ok = s.timezone(); !ok {
+			return fail()
+		}
+		noTimezone = false
+	}
+	if !s.done() {
+		return fail()
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, nanos, loc), noTimezone, nil
+}
+
+func formatTemporal(l layout, t time.Time, noTimezone bool) string {
+	var b strings.Builder
+	switch {
+	case l.year:
+		year := t.Year()
+		if year < 0 {
+			b.WriteString("-")
+			year = -year
+		}
+		fmt.Fprintf(&b, "%04d", year)
+	case l.month:
+		b.WriteString("--")
+	case l.day:
+		b.WriteString("---")
+	}
+	if l.month {
+		if l.year {
+			b.WriteString("-")
+		}
+		fmt.Fprintf(&b, "%02d", int(t.Month()))
+	}
+	if l.day {
+		if l.month {
+			b.WriteString("-")
+		}
+		fmt.Fprintf(&b, "%02d", t.Day())
+	}
+	if l.clock {
+		if l.year {
+			b.WriteString("T")
+		}
+		fmt.Fprintf(&b, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+		if t.Nanosecond() != 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond()), "0"))
+		}
+	}
+	if noTimezone {
+		return b.String()
+	}
+	_, offset := t.Zone()
+	if offset == 0 {
+		b.WriteString("Z")
+		return b.String()
+	}
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	fmt.Fprintf(&b, "%c%02d:%02d", sign, offset/3600, offset%3600/60)
+	return b.String()
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// scanner reads the fields of lexical representation of date or time.
+type scanner struct {
+	text string
+	pos  int
+}
+
+func (s *scanner) done() bool {
+	return s.pos == len(s.text)
+}
+
+func (s *scanner) literal(lit string) bool {
+	if !strings.HasPrefix(s.text[s.pos:], lit) {
+		return false
+	}
+	s.pos += len(lit)
+	return true
+}
+
+func (s *scanner) run() string {
+	start := s.pos
+	for s.pos < len(s.text) && s.text[s.pos] >= '0' && s.text[s.pos] <= '9' {
+		s.pos++
+	}
+	return s.text[start:s.pos]
+}
+
+func (s *scanner) digits(n int) (int, bool) {
+	run := s.run()
+	if len(run) != n {
+		return 0, false
+	}
+	value, err := strconv.Atoi(run)
+	return value, err == nil
+}
+
+// year has at least four digits, leading zeros are allowed only up to four digits.
+func (s *scanner) year() (int, bool) {
+	negative := s.literal("-")
+	run := s.run()
+	if len(run) < 4 || (len(run) > 4 && run[0] == '0') {
+		return 0, false
+	}
+	year, err := strconv.Atoi(run)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		year = -year
+	}
+	return year, true
+}
+
+func (s *scanner) fraction() (int, bool) {
+	run := s.run()
+	if run == "" {
+		return 0, false
+	}
+	run = (run + "000000000")[:9]
+	nanos, err := strconv.Atoi(run)
+	return nanos, err == nil
+}
+
+func (s *scanner) timezone() (*time.Location, bool) {
+	if s.literal("Z") {
+		return time.UTC, true
+	}
+	sign := 1
+	switch {
+	case s.literal("+"):
+	case s.literal("-"):
+		sign = -1
+	default:
+		return nil, false
+	}
+	hours, ok := s.digits(2)
+	if !ok || !s.literal(":") {
+		return nil, false
+	}
+	minutes, ok := s.digits(2)
+	if !ok || minutes > 59 || hours*60+minutes > 14*60 {
+		return nil, false
+	}
+	offset := sign * (hours*3600 + minutes*60)
+	if offset == 0 {
+		return time.UTC, true
+	}
+	return time.FixedZone("", offset), true
+}
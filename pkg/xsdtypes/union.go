@@ -0,0 +1,25 @@
+package xsdtypes
+
+import (
+	"fmt"
+)
+
+// UnionError is returned when value does not belong to any of the member types of xsd:union.
+type UnionError struct {
+	Type  string
+	Value string
+}
+
+func (e *UnionError) Error() string {
+	return fmt.Sprintf("xsdtypes: value '%s' does not match any member type of %s", e.Value, e.Type)
+}
+
+// OneOf reports whether text equals to one of the enumerated values.
+func OneOf(text string, values ...string) bool {
+	for _, value := range values {
+		if text == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package xsdtypes
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by all the generated types. Validate checks the value against the constraints
+// given by the schema (facets of simple types), recursively for attributes and child elements.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError locates invalid value within the document.
+type ValidationError struct {
+	Path string // XPath-like location (items are indexed from 1), relative to the validated value
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Field of generated struct to be validated.
+type Field struct {
+	Name     string // XML name of the attribute (prefixed by @) or element, empty for wildcards
+	Value    any
+	Optional bool // zero value of optional field denotes absent attribute or element
+}
+
+// ValidateFields validates each of the fields, returning the first error encountered.
+func ValidateFields(fields []Field) error {
+	for _, field := range fields {
+		if field.Optional && (field.Value == nil || reflect.ValueOf(field.Value).IsZero()) {
+			continue
+		}
+		if err := Validate(field.Name, field.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateItems validates each item of list value.
+func ValidateItems[L ~[]T, T any](list L) error {
+	for idx, item := range list {
+		if err := Validate(fmt.Sprintf("[%d]", idx+1), item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates value found at the given path. Values of types that do not implement Validator are
+// looked into, if these are pointers, interfaces or slices, otherwise they are considered valid.
+func Validate(path string, value any) error {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if validator, ok := value.(Validator); ok {
+		return locate(path, validator.Validate())
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return Validate(path, v.Elem().Interface())
+	case reflect.Slice:
+		for idx := 0; idx < v.Len(); idx++ {
+			if err := Validate(fmt.Sprintf("%s[%d]", path, idx+1), v.Index(idx).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func locate(path string, err error) error {
+	if err == nil || path == "" {
+		return err
+	}
+	var located *ValidationError
+	if errors.As(err, &located) {
+		separator := "/"
+		if located.Path[0] == '[' {
+			separator = ""
+		}
+		return &ValidationError{Path: path + separator + located.Path, Err: located.Err}
+	}
+	return &ValidationError{Path: path, Err: err}
+}
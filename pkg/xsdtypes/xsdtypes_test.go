@@ -0,0 +1,918 @@
+package xsdtypes_test
+
+import (
+	"encoding/xml"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocomply/xsd2go/pkg/xsdtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type intList []int
+
+func (l intList) MarshalText() ([]byte, error) {
+	return xsdtypes.MarshalList(l)
+}
+
+func (l *intList) UnmarshalText(text []byte) error {
+	return xsdtypes.UnmarshalList(text, l)
+}
+
+func TestListRoundTrip(t *testing.T) {
+	type doc struct {
+		XMLName xml.Name `xml:"doc"`
+		Attr    intList  `xml:"attr,attr"`
+		Items   intList  `xml:"items"`
+	}
+
+	var d doc
+	err := xml.Unmarshal([]byte("<doc attr=\" 1  2\t3 \"><items>\n  4\n  5\n</items></doc>"), &d)
+	require.NoError(t, err)
+	assert.Equal(t, intList{1, 2, 3}, d.Attr)
+	assert.Equal(t, intList{4, 5}, d.Items)
+
+	out, err := xml.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `<doc attr="1 2 3"><items>4 5</items></doc>`, string(out))
+
+	assert.Error(t, xml.Unmarshal([]byte(`<doc attr="1 x"></doc>`), &d))
+}
+
+func TestUnmarshalText(t *testing.T) {
+	var b bool
+	require.NoError(t, xsdtypes.UnmarshalText(&b, " 1 "))
+	assert.True(t, b)
+	assert.Error(t, xsdtypes.UnmarshalText(&b, "yes"))
+
+	var f float64
+	require.NoError(t, xsdtypes.UnmarshalText(&f, "-INF"))
+	text, err := xsdtypes.MarshalText(f)
+	require.NoError(t, err)
+	assert.Equal(t, "-INF", text)
+}
+
+func TestAnyElementRoundTrip(t *testing.T) {
+	type doc struct {
+		XMLName  xml.Name              `xml:"doc"`
+		Known    string                `xml:"known"`
+		Any      []xsdtypes.AnyElement `xml:",any"`
+		AnyAttrs []xml.Attr            `xml:",any,attr"`
+	}
+
+	var d doc
+	input := `<doc a="1"><known>k</known><ext:foo xmlns:ext="urn:ext" b="2"><ext:bar>x</ext:bar></ext:foo></doc>`
+	require.NoError(t, xml.Unmarshal([]byte(input), &d))
+	assert.Equal(t, "k", d.Known)
+	require.Len(t, d.Any, 1)
+	assert.Equal(t, xml.Name{Space: "urn:ext", Local: "foo"}, d.Any[0].XMLName)
+	assert.Equal(t, `<ext:bar>x</ext:bar>`, d.Any[0].InnerXML)
+	require.Len(t, d.AnyAttrs, 1)
+
+	out, err := xml.Marshal(d)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(out), `xmlns:ext="urn:ext"`)
+
+	var again doc
+	require.NoError(t, xml.Unmarshal(out, &again))
+	assert.Equal(t, d.Any[0].XMLName, again.Any[0].XMLName)
+	assert.Equal(t, d.Any[0].InnerXML, again.Any[0].InnerXML)
+}
+
+func TestWildcard(t *testing.T) {
+	for namespace, allowed := range map[string][]string{
+		"":                              {"", "urn:target", "urn:ext"},
+		"##any":                         {"", "urn:target", "urn:ext"},
+		"##other":                       {"urn:ext"},
+		"##targetNamespace":             {"urn:target"},
+		"##local urn:ext":               {"", "urn:ext"},
+		"##targetNamespace ##local":     {"", "urn:target"},
+		"urn:ext ##targetNamespace":     {"urn:target", "urn:ext"},
+		"urn:other ##other ##local":     {"", "urn:ext", "urn:other"},
+		"##targetNamespace urn:unknown": {"urn:target"},
+	} {
+		wildcard := xsdtypes.Wildcard{Namespace: namespace, TargetNamespace: "urn:target"}
+		for _, space := range []string{"", "urn:target", "urn:ext"} {
+			assert.Equal(t, slices.Contains(allowed, space), wildcard.Allows(space), "%q allows %q", namespace, space)
+		}
+	}
+
+	type doc struct {
+		XMLName  xml.Name              `xml:"urn:target doc"`
+		Any      []xsdtypes.AnyElement `xml:",any"`
+		AnyAttrs []xml.Attr            `xml:",any,attr"`
+	}
+	var d doc
+	input := `<doc xmlns="urn:target" xmlns:ext="urn:ext" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+		`xsi:nil="false" ext:a="1"><ext:foo/><bar/></doc>`
+	require.NoError(t, xml.Unmarshal([]byte(input), &d))
+	other := xsdtypes.Wildcard{Namespace: "##other", TargetNamespace: "urn:target"}
+	assert.NoError(t, xsdtypes.WildcardAttrs{Wildcard: other, Attrs: d.AnyAttrs}.Validate())
+	err := xsdtypes.WildcardElements{Wildcard: other, Elements: d.Any}.Validate()
+	require.ErrorIs(t, err, xsdtypes.ErrWildcardNamespace)
+	assert.EqualError(t, err, "bar: namespace not allowed by wildcard: {urn:target}bar")
+
+	local := xsdtypes.Wildcard{Namespace: "##local", TargetNamespace: "urn:target"}
+	err = xsdtypes.Validate("doc", xsdtypes.WildcardAttrs{Wildcard: local, Attrs: d.AnyAttrs})
+	assert.EqualError(t, err, "doc/@a: namespace not allowed by wildcard: {urn:ext}a")
+}
+
+// Types of substitution group with abstract head <object>, as generated by xsd2go.
+type objectSubstitute interface {
+	objectSubstitute()
+}
+
+type fileObject struct {
+	XMLName xml.Name `xml:"file_object"`
+	Id      string   `xml:"id,attr"`
+	Path    string   `xml:"path"`
+}
+
+func (*fileObject) objectSubstitute() {}
+
+type processObject struct {
+	XMLName xml.Name `xml:"process_object"`
+	Id      string   `xml:"id,attr"`
+	Pid     int      `xml:"pid"`
+}
+
+func (*processObject) objectSubstitute() {}
+
+var objectSubstitutes = xsdtypes.Registry[objectSubstitute]{
+	{Space: "urn:sg", Local: "file_object"}:    func() objectSubstitute { return &fileObject{} },
+	{Space: "urn:sg", Local: "process_object"}: func() objectSubstitute { return &processObject{} },
+}
+
+type objects struct {
+	XMLName xml.Name           `xml:"objects"`
+	Comment string             `xml:"comment,omitempty"`
+	Object  []objectSubstitute `xml:"object"`
+}
+
+func (t *objects) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain objects
+	return xsdtypes.DecodeElement(d, &start, (*plain)(t), func(d *xml.Decoder, el xml.StartElement) (bool, error) {
+		if value, ok := objectSubstitutes.New(el.Name); ok {
+			if err := d.DecodeElement(value, &el); err != nil {
+				return true, err
+			}
+			t.Object = append(t.Object, value)
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+func TestSubstitutionGroupRoundTrip(t *testing.T) {
+	doc := `<objects xmlns="urn:sg"><comment>c</comment><file_object id="1"><path>/bin</path></file_object>` +
+		`<process_object id="2"><pid>5</pid></process_object></objects>`
+	var o objects
+	require.NoError(t, xml.Unmarshal([]byte(doc), &o))
+	assert.Equal(t, "c", o.Comment)
+	require.Len(t, o.Object, 2)
+	assert.Equal(t, &fileObject{XMLName: xml.Name{Space: "urn:sg", Local: "file_object"}, Id: "1", Path: "/bin"}, o.Object[0])
+	assert.Equal(t, &processObject{XMLName: xml.Name{Space: "urn:sg", Local: "process_object"}, Id: "2", Pid: 5}, o.Object[1])
+
+	// Names are marshalled without namespace, these fall back to matching the members by local name
+	unqualified := `<objects><comment>c</comment><file_object id="1"><path>/bin</path></file_object>` +
+		`<process_object id="2"><pid>5</pid></process_object></objects>`
+	out, err := xml.Marshal(o)
+	require.NoError(t, err)
+	assert.Equal(t, unqualified, string(out))
+	var again objects
+	require.NoError(t, xml.Unmarshal(out, &again))
+	require.Len(t, again.Object, 2)
+	assert.Equal(t, "/bin", again.Object[0].(*fileObject).Path)
+	assert.Equal(t, 5, again.Object[1].(*processObject).Pid)
+
+	_, ok := objectSubstitutes.New(xml.Name{Space: "urn:other", Local: "file_object"})
+	assert.False(t, ok, "qualified name of other namespace matches by local name")
+	unqualifiedMembers := xsdtypes.Registry[objectSubstitute]{{Local: "file_object"}: func() objectSubstitute { return &fileObject{} }}
+	_, ok = unqualifiedMembers.New(xml.Name{Space: "urn:sg", Local: "file_object"})
+	assert.True(t, ok)
+
+	// Abstract head is not a member of its substitution group, so that it is never instantiated. Its occurrence
+	// within the document is left nil, in document order along with the members decoded by the registry.
+	_, ok = objectSubstitutes.New(xml.Name{Space: "urn:sg", Local: "object"})
+	assert.False(t, ok)
+	var withHead objects
+	require.NoError(t, xml.Unmarshal([]byte(`<objects xmlns="urn:sg"><object id="4"/><file_object id="5"/></objects>`), &withHead))
+	require.Len(t, withHead.Object, 2)
+	assert.Nil(t, withHead.Object[0])
+	assert.Equal(t, "5", withHead.Object[1].(*fileObject).Id)
+}
+
+func TestXsiType(t *testing.T) {
+	d := xml.NewDecoder(strings.NewReader(`<doc xmlns:ex="urn:example" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<item xsi:type="ex:Derived"/><item xmlns="urn:default" xsi:type="Local"/><item/></doc>`))
+	starts := []xml.StartElement{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			starts = append(starts, start)
+		}
+	}
+	require.Len(t, starts, 4)
+	assert.Equal(t, xml.Name{Space: "urn:example", Local: "Derived"}, xsdtypes.XsiType(starts[1], starts[0]))
+	assert.Equal(t, xml.Name{Local: "Derived"}, xsdtypes.XsiType(starts[1]))
+	assert.Equal(t, xml.Name{Space: "urn:default", Local: "Local"}, xsdtypes.XsiType(starts[2], starts[0]))
+	assert.Equal(t, xml.Name{}, xsdtypes.XsiType(starts[3], starts[0]))
+
+	start := xml.StartElement{Name: xml.Name{Local: "item"}}
+	xsdtypes.SetXsiType(&start, xml.Name{Space: "urn:example", Local: "Derived"}, "ex")
+	var buf strings.Builder
+	e := xml.NewEncoder(&buf)
+	require.NoError(t, e.EncodeToken(start))
+	require.NoError(t, e.EncodeToken(start.End()))
+	require.NoError(t, e.Flush())
+	assert.Equal(t, `<item xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:ex="urn:example" xsi:type="ex:Derived"></item>`, buf.String())
+
+	for declared, expected := range map[string]string{
+		"Derived": `<item><id>1</id></item>`,
+		"Base":    `<item xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:ex="urn:example" xsi:type="ex:Derived"><id>1</id></item>`,
+	} {
+		buf.Reset()
+		e := xml.NewEncoder(&buf)
+		start := xml.StartElement{Name: xml.Name{Local: "item"}}
+		require.NoError(t, xsdtypes.MarshalXsiTyped(e, start, &derived{Id: 1}, xml.Name{Space: "urn:example", Local: declared}))
+		require.NoError(t, e.Flush())
+		assert.Equal(t, expected, buf.String())
+	}
+}
+
+type derived struct {
+	Id int `xml:"id"`
+}
+
+func (*derived) XsiType() (xml.Name, string) {
+	return xml.Name{Space: "urn:example", Local: "Derived"}, "ex"
+}
+
+func TestNillableRoundTrip(t *testing.T) {
+	type doc struct {
+		XMLName xml.Name                    `xml:"doc"`
+		Name    xsdtypes.Nillable[string]   `xml:"name"`
+		Age     *xsdtypes.Nillable[int]     `xml:"age,omitempty"`
+		Phones  []xsdtypes.Nillable[string] `xml:"phone"`
+	}
+
+	var d doc
+	err := xml.Unmarshal([]byte(`<doc xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">`+
+		`<name xsi:nil="true"/><age>42</age><phone>123</phone><phone xsi:nil="1"></phone></doc>`), &d)
+	require.NoError(t, err)
+	assert.Equal(t, xsdtypes.Nillable[string]{Nil: true}, d.Name)
+	assert.Equal(t, &xsdtypes.Nillable[int]{Value: 42}, d.Age)
+	assert.Equal(t, []xsdtypes.Nillable[string]{{Value: "123"}, {Nil: true}}, d.Phones)
+
+	d.Age = nil
+	out, err := xml.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `<doc><name xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></name>`+
+		`<phone>123</phone><phone xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></phone></doc>`, string(out))
+}
+
+// config is generated for element declaring default and fixed values.
+type config struct {
+	XMLName xml.Name                 `xml:"config"`
+	Version string                   `xml:"version,attr,omitempty"`
+	Mode    string                   `xml:"mode,attr,omitempty"`
+	Retries int                      `xml:"retries"`
+	Timeout *float64                 `xml:"timeout,omitempty"`
+	Verbose *xsdtypes.Nillable[bool] `xml:"verbose,omitempty"`
+	Unit    string                   `xml:"unit,omitempty"`
+}
+
+func (t *config) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain config
+	return xsdtypes.DecodeDefaults(d, &start, (*plain)(t), t.defaultValues(), nil)
+}
+
+func (t config) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain config
+	if err := xsdtypes.ApplyFixed(t.defaultValues()); err != nil {
+		return err
+	}
+	return e.EncodeElement(plain(t), start)
+}
+
+func (t *config) defaultValues() []xsdtypes.Default {
+	return []xsdtypes.Default{
+		{Name: "@version", Field: &t.Version, Value: "1.2", Fixed: true},
+		{Name: "@mode", Field: &t.Mode, Value: "strict"},
+		{Name: "retries", Field: &t.Retries, Value: "3"},
+		{Name: "timeout", Field: &t.Timeout, Value: " 1.5 "},
+		{Name: "verbose", Field: &t.Verbose, Value: "true"},
+		{Name: "unit", Field: &t.Unit, Value: "mm", Fixed: true},
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	var c config
+	require.NoError(t, xml.Unmarshal([]byte(`<config><retries/></config>`), &c))
+	assert.Equal(t, config{XMLName: xml.Name{Local: "config"}, Version: "1.2", Mode: "strict", Retries: 3}, c)
+	out, err := xml.Marshal(c)
+	require.NoError(t, err)
+	assert.Equal(t, `<config version="1.2" mode="strict"><retries>3</retries></config>`, string(out))
+
+	doc := `<config xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" mode="lax"><retries>0</retries>` +
+		`<timeout></timeout><verbose xsi:nil="true"/><unit/></config>`
+	c = config{}
+	require.NoError(t, xml.Unmarshal([]byte(doc), &c))
+	assert.Equal(t, "lax", c.Mode)
+	assert.Equal(t, 0, c.Retries)
+	assert.Equal(t, 1.5, *c.Timeout)
+	assert.Equal(t, xsdtypes.Nillable[bool]{Nil: true}, *c.Verbose)
+	assert.Equal(t, "mm", c.Unit)
+
+	var fixedErr *xsdtypes.FixedValueError
+	require.ErrorAs(t, xml.Unmarshal([]byte(`<config version="1.3"><retries/></config>`), &c), &fixedErr)
+	assert.Equal(t, "@version", fixedErr.Name)
+	require.ErrorAs(t, xml.Unmarshal([]byte(`<config><retries/><unit>cm</unit></config>`), &c), &fixedErr)
+	assert.Equal(t, "unit", fixedErr.Name)
+
+	c = config{Version: "1.3", Unit: "cm"}
+	out, err = xml.Marshal(c)
+	require.NoError(t, err)
+	assert.Equal(t, `<config version="1.2"><retries>0</retries><unit>mm</unit></config>`, string(out))
+
+	timeout := 2.5
+	assert.Equal(t, 2.5, xsdtypes.OrDefault(&timeout, "1.5"))
+	assert.Equal(t, 1.5, xsdtypes.OrDefault[float64](nil, " 1.5 "))
+	assert.Equal(t, xsdtypes.Nillable[bool]{Value: true}, xsdtypes.OrDefault[xsdtypes.Nillable[bool]](nil, "true"))
+	assert.Zero(t, xsdtypes.OrDefault[int](nil, "x"))
+}
+
+// node is generated for recursive element of qualified child elements, declaring default value.
+type node struct {
+	XMLName xml.Name `xml:"urn:example node"`
+	Label   string   `xml:"label"`
+	Node    *node    `xml:"node"`
+}
+
+func (t *node) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain node
+	return xsdtypes.DecodeDefaults(d, &start, (*plain)(t), []xsdtypes.Default{
+		{Name: "label", Space: "urn:example", Field: &t.Label, Value: "none"},
+	}, nil)
+}
+
+func TestDefaultsNested(t *testing.T) {
+	var n node
+	doc := `<ex:node xmlns:ex="urn:example"><ex:label>a</ex:label><ex:node><ex:label/><ex:node>` +
+		`<label xmlns="urn:other"/></ex:node></ex:node></ex:node>`
+	require.NoError(t, xml.Unmarshal([]byte(doc), &n))
+	assert.Equal(t, "a", n.Label)
+	require.NotNil(t, n.Node)
+	assert.Equal(t, "none", n.Node.Label)
+	require.NotNil(t, n.Node.Node)
+	// Element of another namespace is not the one declaring the default value
+	assert.Empty(t, n.Node.Node.Label)
+	assert.Nil(t, n.Node.Node.Node)
+}
+
+type code string
+
+var facetsCode = xsdtypes.Facets{
+	Type:       "code",
+	Patterns:   xsdtypes.MustCompilePatterns("^(?:[A-Z]{2})$"),
+	WhiteSpace: "collapse",
+}
+
+func (c code) Validate() error {
+	return facetsCode.Validate(c)
+}
+
+type codeList []code
+
+func (l codeList) Validate() error {
+	return xsdtypes.ValidateItems(l)
+}
+
+func TestFacets(t *testing.T) {
+	percentage := xsdtypes.Facets{Type: "percentage", MinInclusive: "0", MaxExclusive: "100", TotalDigits: "4", FractionDigits: "2"}
+	assert.NoError(t, percentage.Validate(12.5))
+	assert.NoError(t, percentage.Validate(0))
+	assert.Error(t, percentage.Validate(100))
+	assert.Error(t, percentage.Validate(-1))
+	assert.Error(t, percentage.Validate(1.125))
+
+	var facetErr *xsdtypes.FacetError
+	require.ErrorAs(t, percentage.Validate(99.999), &facetErr)
+	assert.Equal(t, "totalDigits", facetErr.Facet)
+
+	length := xsdtypes.Facets{Type: "name", MinLength: "2", MaxLength: "3", Enumeration: []string{"ab", "čáp", "abcd"}}
+	assert.NoError(t, length.Validate("čáp"))
+	assert.Error(t, length.Validate("abcd"))
+	assert.Error(t, length.Validate("abc"))
+
+	numbers := xsdtypes.Facets{Type: "numbers", Enumeration: []string{"1.0"}}
+	assert.NoError(t, numbers.Validate(1.0))
+
+	assert.NoError(t, code(" CZ ").Validate())
+	assert.Error(t, code("cz").Validate())
+}
+
+func TestValidate(t *testing.T) {
+	type record struct {
+		ID        code
+		Countries *codeList
+	}
+	validate := func(r record) error {
+		return xsdtypes.ValidateFields([]xsdtypes.Field{
+			{Name: "@id", Value: r.ID},
+			{Name: "countries", Value: r.Countries, Optional: true},
+		})
+	}
+
+	assert.NoError(t, validate(record{ID: "AB"}))
+	assert.NoError(t, validate(record{ID: "AB", Countries: &codeList{}}))
+	assert.NoError(t, validate(record{ID: "AB", Countries: &codeList{"CZ", "SK"}}))
+
+	var validationErr *xsdtypes.ValidationError
+	require.ErrorAs(t, validate(record{ID: "AB", Countries: &codeList{"CZ", "sk"}}), &validationErr)
+	assert.Equal(t, "countries[2]", validationErr.Path)
+	require.ErrorAs(t, validate(record{ID: "abc"}), &validationErr)
+	assert.Equal(t, "@id", validationErr.Path)
+}
+
+func TestIdentityConstraints(t *testing.T) {
+	type item struct {
+		Ref string `xml:"ref,attr"`
+	}
+	type entry struct {
+		XMLName xml.Name `xml:"entry"`
+		ID      string   `xml:"id,attr,omitempty"`
+		Name    string   `xml:"name"`
+	}
+	type catalog struct {
+		XMLName xml.Name `xml:"catalog"`
+		Entries []*entry `xml:"entry"`
+		Refs    []item   `xml:"ref"`
+	}
+	constraints := []xsdtypes.IdentityConstraint{
+		{Element: "catalog", Kind: "key", Name: "entryKey", Selector: "c:entry", Fields: []string{"@id"}},
+		{Element: "catalog", Kind: "unique", Name: "entryName", Selector: "entry", Fields: []string{"c:name"}},
+		{Element: "catalog", Kind: "keyref", Name: "entryRef", Refer: "entryKey", Selector: "ref | .//c:item", Fields: []string{"@ref"}},
+	}
+
+	doc := catalog{
+		Entries: []*entry{{ID: "a", Name: "A"}, {ID: "b", Name: "B"}},
+		Refs:    []item{{Ref: "a"}, {Ref: "b"}},
+	}
+	require.NoError(t, xsdtypes.ValidateIdentityConstraints(doc, constraints))
+
+	doc.Entries = append(doc.Entries, &entry{ID: "a", Name: "C"}, &entry{Name: "A"})
+	doc.Refs = append(doc.Refs, item{Ref: "x"})
+	err := xsdtypes.ValidateIdentityConstraints(&doc, constraints)
+	require.ErrorIs(t, err, xsdtypes.ErrDuplicateKey)
+	require.ErrorIs(t, err, xsdtypes.ErrMissingKeyField)
+	require.ErrorIs(t, err, xsdtypes.ErrDanglingKeyref)
+
+	var constraintErr *xsdtypes.IdentityConstraintError
+	require.ErrorAs(t, err, &constraintErr)
+	assert.Equal(t, "/catalog/entry[3]", constraintErr.Path)
+	assert.Contains(t, err.Error(), "/catalog/entry[4]: duplicate value 'A' of unique 'entryName'")
+	assert.Contains(t, err.Error(), "/catalog/ref[3]: dangling reference 'x' of keyref 'entryRef'")
+
+	malformed := []xsdtypes.IdentityConstraint{{Element: "catalog", Kind: "key", Name: "bad", Selector: "@id"}}
+	assert.Error(t, xsdtypes.ValidateIdentityConstraints(doc, malformed))
+}
+
+func TestIdentityConstraintsAnyElements(t *testing.T) {
+	// Lone child elements are generated with xml:",any" tag
+	type product struct {
+		Code string `xml:",any"`
+	}
+	type catalog struct {
+		XMLName xml.Name  `xml:"catalog"`
+		Product []product `xml:"product"`
+	}
+	constraints := []xsdtypes.IdentityConstraint{{
+		Element: "catalog", Kind: "key", Name: "codeKey", Selector: "id:product", Fields: []string{"id:code"},
+		AnyElements: map[string]string{"product.Code": "code"},
+	}}
+
+	require.NoError(t, xsdtypes.ValidateIdentityConstraints(catalog{Product: []product{{"a"}, {"b"}}}, constraints))
+	err := xsdtypes.ValidateIdentityConstraints(catalog{Product: []product{{"a"}, {"a"}}}, constraints)
+	require.ErrorIs(t, err, xsdtypes.ErrDuplicateKey)
+	assert.NotErrorIs(t, err, xsdtypes.ErrMissingKeyField)
+}
+
+func TestIdentityConstraintsScope(t *testing.T) {
+	type item struct {
+		ID string `xml:"id,attr"`
+	}
+	type section struct {
+		XMLName xml.Name `xml:"section"`
+		Items   []item   `xml:"item"`
+	}
+	type ref struct {
+		To string `xml:"to,attr"`
+	}
+	type document struct {
+		XMLName  xml.Name  `xml:"document"`
+		Sections []section `xml:"section"`
+		Refs     []ref     `xml:"ref"`
+	}
+	constraints := []xsdtypes.IdentityConstraint{
+		{Element: "section", Kind: "key", Name: "itemKey", Selector: "item", Fields: []string{"@id"}},
+		{Element: "document", Kind: "keyref", Name: "itemRef", Refer: "itemKey", Selector: "ref", Fields: []string{"@to"}},
+		{Element: "section", Kind: "keyref", Name: "documentRef", Refer: "documentKey", Selector: "item", Fields: []string{"@id"}},
+		{Element: "document", Kind: "key", Name: "documentKey", Selector: "ref", Fields: []string{"@to"}},
+	}
+
+	// Keys declared by descendants are visible to the keyref, keys of ancestors are not
+	doc := document{
+		Sections: []section{{Items: []item{{"a"}, {"x"}}}, {Items: []item{{"b"}}}},
+		Refs:     []ref{{"a"}, {"b"}},
+	}
+	require.NoError(t, xsdtypes.ValidateIdentityConstraints(doc, constraints))
+
+	doc.Refs = append(doc.Refs, ref{"c"})
+	err := xsdtypes.ValidateIdentityConstraints(doc, constraints)
+	require.ErrorIs(t, err, xsdtypes.ErrDanglingKeyref)
+	assert.Equal(t, "/document/ref[3]: dangling reference 'c' of keyref 'itemRef'", err.Error())
+}
+
+func TestTemporalRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"2024-02-29T13:05:00Z",
+		"2024-02-29T13:05:00.25+01:30",
+		"-0044-03-15T12:00:00",
+		"12345-01-01T00:00:00.000000001-14:00",
+	} {
+		var v xsdtypes.DateTime
+		require.NoError(t, v.UnmarshalText([]byte(text)), text)
+		out, err := v.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, text, string(out))
+	}
+
+	var dt xsdtypes.DateTime
+	require.NoError(t, dt.UnmarshalText([]byte(" 1999-12-31T24:00:00.000-05:00 ")))
+	assert.Equal(t, "2000-01-01T00:00:00-05:00", dt.String())
+	assert.True(t, dt.Time.Equal(time.Date(2000, 1, 1, 5, 0, 0, 0, time.UTC)))
+
+	require.NoError(t, dt.UnmarshalText([]byte("2001-10-26T21:32:52")))
+	assert.True(t, dt.NoTimezone)
+	assert.Equal(t, time.Date(2001, 10, 26, 21, 32, 52, 0, time.UTC), dt.Time)
+
+	for text, parse := range map[string]func(string) (string, error){
+		"2002-10-10+00:00": temporal[xsdtypes.Date],
+		"24:00:00":         temporal[xsdtypes.Time],
+		"-0001":            temporal[xsdtypes.GYear],
+		"2004-04Z":         temporal[xsdtypes.GYearMonth],
+		"--02-29":          temporal[xsdtypes.GMonthDay],
+		"---31":            temporal[xsdtypes.GDay],
+		"--12":             temporal[xsdtypes.GMonth],
+	} {
+		out, err := parse(text)
+		require.NoError(t, err, text)
+		switch text {
+		case "2002-10-10+00:00":
+			assert.Equal(t, "2002-10-10Z", out)
+		case "24:00:00":
+			assert.Equal(t, "00:00:00", out)
+		default:
+			assert.Equal(t, text, out)
+		}
+	}
+
+	for _, text := range []string{
+		"2001-02-29T00:00:00", "2001-10-26", "01-10-26T21:32:52", "02001-10-26T21:32:52", "2001-10-26T21:32",
+		"2001-10-26T24:00:01", "2001-10-26T21:32:52.", "2001-10-26T21:32:52+15:00", "2001-10-26T21:32:52 Z",
+	} {
+		var v xsdtypes.DateTime
+		var temporalErr *xsdtypes.TemporalError
+		assert.ErrorAs(t, v.UnmarshalText([]byte(text)), &temporalErr, text)
+	}
+}
+
+func temporal[T any, PT interface {
+	*T
+	UnmarshalText([]byte) error
+	MarshalText() ([]byte, error)
+}](text string) (string, error) {
+	var v T
+	if err := PT(&v).UnmarshalText([]byte(text)); err != nil {
+		return "", err
+	}
+	out, err := PT(&v).MarshalText()
+	return string(out), err
+}
+
+func TestTemporalXML(t *testing.T) {
+	type doc struct {
+		XMLName xml.Name           `xml:"doc"`
+		Updated xsdtypes.DateTime  `xml:"updated,attr,omitempty"`
+		Day     xsdtypes.Date      `xml:"day"`
+		Due     *xsdtypes.DateTime `xml:"due"`
+	}
+
+	var d doc
+	require.NoError(t, xml.Unmarshal([]byte(`<doc><day>2024-01-02-08:00</day></doc>`), &d))
+	assert.True(t, d.Updated.Time.IsZero())
+	assert.Nil(t, d.Due)
+	assert.Equal(t, -8*3600, func() int { _, offset := d.Day.Time.Zone(); return offset }())
+
+	out, err := xml.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `<doc><day>2024-01-02-08:00</day></doc>`, string(out))
+}
+
+func TestTemporalFacets(t *testing.T) {
+	facets := xsdtypes.Facets{
+		Type:         "meeting",
+		MinInclusive: "2024-01-01T09:00:00Z",
+		MaxExclusive: "2024-01-01T17:00:00Z",
+		Enumeration:  []string{"2024-01-01T12:00:00Z", "2024-01-01T10:00:00-02:00", "2024-01-01T15:00:00"},
+	}
+	value := func(text string) xsdtypes.DateTime {
+		var v xsdtypes.DateTime
+		require.NoError(t, v.UnmarshalText([]byte(text)))
+		return v
+	}
+	assert.NoError(t, facets.Validate(value("2024-01-01T13:00:00+01:00")))
+	assert.NoError(t, facets.Validate(value("2024-01-01T12:00:00Z")))
+	assert.Error(t, facets.Validate(value("2024-01-01T13:00:00Z")))
+	// Value without timezone is not comparable with the bounds less than 14 hours away
+	assert.Error(t, facets.Validate(value("2024-01-01T15:00:00")))
+
+	// Restrictions are generated as structs embedding the base type
+	type workingHour struct {
+		xsdtypes.Time
+	}
+	hours := xsdtypes.Facets{Type: "WorkingHour", MinInclusive: "08:00:00", MaxExclusive: "18:00:00"}
+	var hour workingHour
+	require.NoError(t, hour.UnmarshalText([]byte("17:59:59.5")))
+	assert.NoError(t, hours.Validate(hour))
+	require.NoError(t, hour.UnmarshalText([]byte("18:00:00")))
+	assert.Error(t, hours.Validate(hour))
+}
+
+func TestDuration(t *testing.T) {
+	for text, expected := range map[string]string{
+		"P1Y2M3DT4H5M6.7S": "P1Y2M3DT4H5M6.7S",
+		"-P10D":            "-P10D",
+		"PT36H":            "PT36H",
+		"PT90M":            "PT1H30M",
+		"P0Y0DT0.000S":     "PT0S",
+		"-PT0S":            "PT0S",
+		"P1MT0.000000001S": "P1MT0.000000001S",
+	} {
+		var d xsdtypes.Duration
+		require.NoError(t, d.UnmarshalText([]byte(text)), text)
+		assert.Equal(t, expected, d.String(), text)
+	}
+
+	for _, text := range []string{"P", "PT", "1D", "P1D2Y", "P-1D", "PT1.S", "PT.5S", "P1DT", "P1.5D", "PT99999999999H"} {
+		var d xsdtypes.Duration
+		var durationErr *xsdtypes.DurationError
+		assert.ErrorAs(t, d.UnmarshalText([]byte(text)), &durationErr, text)
+	}
+
+	var dayTime xsdtypes.DayTimeDuration
+	require.NoError(t, dayTime.UnmarshalText([]byte("P1DT2H")))
+	assert.Equal(t, 26*time.Hour, time.Duration(dayTime.Days)*24*time.Hour+dayTime.Time)
+	assert.Error(t, dayTime.UnmarshalText([]byte("P1M")))
+	var yearMonth xsdtypes.YearMonthDuration
+	require.NoError(t, yearMonth.UnmarshalText([]byte("-P1Y6M")))
+	assert.True(t, yearMonth.Negative)
+	assert.Error(t, yearMonth.UnmarshalText([]byte("P1Y1D")))
+
+	type length struct {
+		xsdtypes.DayTimeDuration
+	}
+	var l length
+	facets := xsdtypes.Facets{Type: "Length", MaxInclusive: "PT8H"}
+	require.NoError(t, l.UnmarshalText([]byte("PT1H30M")))
+	assert.NoError(t, facets.Validate(l))
+	require.NoError(t, l.UnmarshalText([]byte("P1D")))
+	assert.Error(t, facets.Validate(l))
+}
+
+func TestDurationArithmetic(t *testing.T) {
+	parse := func(text string) xsdtypes.Duration {
+		var d xsdtypes.Duration
+		require.NoError(t, d.UnmarshalText([]byte(text)))
+		return d
+	}
+	jan31 := time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, time.February, 29, 10, 0, 0, 0, time.UTC), parse("P1M").AddTo(jan31))
+	assert.Equal(t, time.Date(2023, time.February, 28, 10, 0, 0, 0, time.UTC), parse("-P11M").AddTo(jan31))
+	assert.Equal(t, time.Date(2024, time.March, 1, 11, 30, 0, 0, time.UTC), parse("P1M1DT1H30M").AddTo(jan31))
+	assert.Equal(t, time.Date(2023, time.December, 31, 9, 0, 0, 0, time.UTC), parse("-P1MT1H").AddTo(jan31))
+
+	for _, c := range []struct {
+		a, b       string
+		expected   int
+		comparable bool
+	}{
+		{"P1Y", "P12M", 0, true},
+		{"P1Y", "P365D", 0, false},
+		{"P1M", "P30D", 0, false},
+		{"P1M", "P32D", -1, true},
+		{"PT24H", "P1D", 0, true},
+		{"-P1D", "PT1S", -1, true},
+	} {
+		res, comparable := parse(c.a).Compare(parse(c.b))
+		assert.Equal(t, c.comparable, comparable, "%s <=> %s", c.a, c.b)
+		assert.Equal(t, c.expected, res, "%s <=> %s", c.a, c.b)
+	}
+
+	facets := xsdtypes.Facets{Type: "timeout", MinExclusive: "PT0S", MaxInclusive: "P1D"}
+	assert.NoError(t, facets.Validate(parse("PT24H")))
+	assert.Error(t, facets.Validate(parse("PT0S")))
+	assert.Error(t, facets.Validate(parse("P1M")))
+}
+
+func TestDecimal(t *testing.T) {
+	type invoice struct {
+		XMLName xml.Name         `xml:"invoice"`
+		Rate    xsdtypes.Decimal `xml:"rate,attr"`
+		Amount  xsdtypes.Decimal `xml:"amount"`
+	}
+	var inv invoice
+	require.NoError(t, xml.Unmarshal([]byte(`<invoice><amount> 12345678901234567890.10 </amount></invoice>`), &inv))
+	assert.Equal(t, xsdtypes.Decimal("12345678901234567890.10"), inv.Amount)
+	assert.Equal(t, xsdtypes.Decimal(""), inv.Rate)
+	out, err := xml.Marshal(inv)
+	require.NoError(t, err)
+	assert.Equal(t, `<invoice><amount>12345678901234567890.10</amount></invoice>`, string(out))
+	assert.Error(t, xml.Unmarshal([]byte(`<invoice><amount>1e3</amount></invoice>`), &inv))
+
+	for _, text := range []string{"-1.5", "+.5", "5.", "007"} {
+		_, err := xsdtypes.ParseDecimal(text)
+		assert.NoError(t, err, text)
+	}
+	for _, text := range []string{"", ".", "+-1", "1.2.3", "NaN", "1/2"} {
+		var numberErr *xsdtypes.NumberError
+		_, err := xsdtypes.ParseDecimal(text)
+		assert.ErrorAs(t, err, &numberErr, text)
+	}
+
+	price, quantity := xsdtypes.Decimal("0.10"), xsdtypes.Decimal("3")
+	assert.Equal(t, xsdtypes.Decimal("0.30"), price.Mul(quantity))
+	assert.Equal(t, xsdtypes.Decimal("3.10"), price.Add(quantity))
+	assert.Equal(t, xsdtypes.Decimal("-2.90"), price.Sub(quantity))
+	assert.Equal(t, xsdtypes.Decimal("0.1"), price.Round(1))
+	assert.Equal(t, xsdtypes.Decimal("-0.13"), xsdtypes.DecimalFromRat(big.NewRat(-1, 8), 2))
+	assert.Equal(t, 0, xsdtypes.Decimal("1.0").Cmp("1"))
+	total, fraction := xsdtypes.Decimal("012.340").Digits()
+	assert.Equal(t, []int{4, 2}, []int{total, fraction})
+
+	facets := xsdtypes.Facets{Type: "amount", MinExclusive: "0", TotalDigits: "22", FractionDigits: "2", Enumeration: []string{"0.5", "12345678901234567890.10"}}
+	assert.NoError(t, facets.Validate(inv.Amount))
+	assert.NoError(t, facets.Validate(xsdtypes.Decimal("0.50")))
+	assert.Error(t, facets.Validate(xsdtypes.Decimal("12345678901234567890.11")))
+
+	type amount struct {
+		xsdtypes.Decimal
+	}
+	sum := amount{"9999999999999999999999.99"}
+	digits := xsdtypes.Facets{Type: "Amount", TotalDigits: "24", FractionDigits: "2"}
+	assert.NoError(t, digits.Validate(sum))
+	sum.Decimal = sum.Add("0.001")
+	assert.Error(t, digits.Validate(sum))
+}
+
+func TestInteger(t *testing.T) {
+	type record struct {
+		XMLName xml.Name                    `xml:"record"`
+		Serial  xsdtypes.PositiveInteger    `xml:"serial,attr"`
+		Count   xsdtypes.NonNegativeInteger `xml:"count,attr"`
+		Balance xsdtypes.Integer            `xml:"balance"`
+	}
+	var r record
+	require.NoError(t, xml.Unmarshal([]byte(`<record serial="+98765432109876543210"><balance>-0</balance></record>`), &r))
+	assert.Equal(t, "98765432109876543210", r.Serial.String())
+	assert.True(t, r.Count.IsAbsent())
+	assert.False(t, r.Balance.IsAbsent())
+	assert.Equal(t, 0, r.Balance.BigInt().Sign())
+	out, err := xml.Marshal(r)
+	require.NoError(t, err)
+	assert.Equal(t, `<record serial="98765432109876543210"><balance>0</balance></record>`, string(out))
+
+	for _, doc := range []string{`<record serial="0"/>`, `<record count="-1"/>`, `<record><balance>1.0</balance></record>`} {
+		var numberErr *xsdtypes.NumberError
+		assert.ErrorAs(t, xml.Unmarshal([]byte(doc), &r), &numberErr, doc)
+	}
+
+	assert.Equal(t, -1, xsdtypes.IntegerFromInt64(-5).Cmp(xsdtypes.NewInteger(big.NewInt(3))))
+	facets := xsdtypes.Facets{Type: "serial", MaxInclusive: "99999999999999999999"}
+	assert.NoError(t, facets.Validate(r.Serial))
+	assert.Error(t, facets.Validate(xsdtypes.NewInteger(new(big.Int).Lsh(big.NewInt(1), 70))))
+}
+
+func TestBinary(t *testing.T) {
+	type signature struct {
+		XMLName xml.Name              `xml:"signature"`
+		Digest  xsdtypes.HexBinary    `xml:"digest,attr"`
+		Salt    xsdtypes.HexBinary    `xml:"salt,attr,omitempty"`
+		Value   xsdtypes.Base64Binary `xml:"value"`
+	}
+	var s signature
+	require.NoError(t, xml.Unmarshal([]byte("<signature digest=\" 0aff \"><value>\n  aGVs\r\n  bG8=\n</value></signature>"), &s))
+	assert.Equal(t, xsdtypes.HexBinary{0x0a, 0xff}, s.Digest)
+	assert.Equal(t, xsdtypes.Base64Binary("hello"), s.Value)
+	out, err := xml.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `<signature digest="0AFF"><value>aGVsbG8=</value></signature>`, string(out))
+
+	var binaryErr *xsdtypes.BinaryError
+	assert.ErrorAs(t, xml.Unmarshal([]byte(`<signature digest="0g"/>`), &s), &binaryErr)
+	assert.ErrorAs(t, xml.Unmarshal([]byte(`<signature><value>aGVsbG8</value></signature>`), &s), &binaryErr)
+
+	facets := xsdtypes.Facets{Type: "digest", Length: "2"}
+	assert.NoError(t, facets.Validate(s.Digest))
+	assert.Error(t, facets.Validate(s.Value))
+
+	type digest struct {
+		xsdtypes.HexBinary
+	}
+	type attachment struct {
+		XMLName xml.Name `xml:"attachment"`
+		Digest  digest   `xml:"digest,attr"`
+	}
+	var a attachment
+	require.NoError(t, xml.Unmarshal([]byte(`<attachment digest="cafe"/>`), &a))
+	assert.NoError(t, facets.Validate(a.Digest))
+	out, err = xml.Marshal(a)
+	require.NoError(t, err)
+	assert.Equal(t, `<attachment digest="CAFE"></attachment>`, string(out))
+	a.Digest.HexBinary = a.Digest.HexBinary[:1]
+	assert.Error(t, facets.Validate(a.Digest))
+}
+
+func TestQName(t *testing.T) {
+	type fault struct {
+		XMLName xml.Name       `xml:"fault"`
+		Code    xsdtypes.QName `xml:"faultcode"`
+		Detail  xsdtypes.QName `xml:"detail,omitempty"`
+	}
+	doc := `<env xmlns:soap="urn:soap" xmlns:app="urn:app"><fault><faultcode>soap:Client</faultcode>` +
+		`<detail xmlns:app="urn:other">app:Invalid</detail></fault></env>`
+	var wrapper struct {
+		Fault fault `xml:"fault"`
+	}
+	require.ErrorIs(t, xml.Unmarshal([]byte(doc), &wrapper), xsdtypes.ErrQNameUnresolved, "prefix declared by ancestor is not known")
+
+	require.NoError(t, xsdtypes.NamespaceDecoder(xml.NewDecoder(strings.NewReader(doc))).Decode(&wrapper))
+	assert.Equal(t, xsdtypes.QName{Name: xml.Name{Space: "urn:soap", Local: "Client"}, Prefix: "soap"}, wrapper.Fault.Code)
+	assert.Equal(t, xml.Name{Space: "urn:other", Local: "Invalid"}, wrapper.Fault.Detail.Name)
+
+	out, err := xml.Marshal(fault{Code: wrapper.Fault.Code, Detail: xsdtypes.QName{Name: xml.Name{Space: "urn:app", Local: "X"}}})
+	require.NoError(t, err)
+	assert.Equal(t, `<fault><faultcode xmlns:soap="urn:soap">soap:Client</faultcode><detail xmlns:ns1="urn:app">ns1:X</detail></fault>`,
+		string(out))
+
+	type part struct {
+		XMLName xml.Name       `xml:"part"`
+		Type    xsdtypes.QName `xml:"type,attr"`
+		Element xsdtypes.QName `xml:"element,attr"`
+	}
+	var p part
+	require.NoError(t, xml.Unmarshal([]byte(`<part xmlns:tns="urn:tns" type="tns:Foo"/>`), &p))
+	assert.Equal(t, xml.Name{Local: "Foo"}, p.Type.Name)
+	start := xml.StartElement{Attr: []xml.Attr{{Name: xml.Name{Space: "xmlns", Local: "tns"}, Value: "urn:tns"}}}
+	require.NoError(t, xsdtypes.ResolveQNames(start, &p.Type, &p.Element))
+	assert.Equal(t, xml.Name{Space: "urn:tns", Local: "Foo"}, p.Type.Name)
+	assert.Zero(t, p.Element)
+	undeclared := xsdtypes.QName{Name: xml.Name{Local: "Foo"}, Prefix: "tns"}
+	assert.ErrorIs(t, xsdtypes.ResolveQNames(xml.StartElement{}, &undeclared), xsdtypes.ErrQNameUnresolved)
+
+	start = xml.StartElement{Name: xml.Name{Local: "part"}}
+	p.Type.Prefix = ""
+	p.Element = xsdtypes.QName{Name: xml.Name{Space: "urn:tns", Local: "Bar"}, Prefix: "tns"}
+	xsdtypes.DeclareQNames(&start, &p.Type, &p.Element)
+	assert.Equal(t, "ns1", p.Type.Prefix)
+	assert.Equal(t, []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:ns1"}, Value: "urn:tns"},
+		{Name: xml.Name{Local: "xmlns:tns"}, Value: "urn:tns"},
+	}, start.Attr)
+
+	_, err = xml.Marshal(part{Type: xsdtypes.QName{Name: xml.Name{Space: "urn:tns", Local: "Foo"}}})
+	assert.ErrorIs(t, err, xsdtypes.ErrQNamePrefix)
+	var qnameErr *xsdtypes.QNameError
+	assert.ErrorAs(t, xml.Unmarshal([]byte(`<part type="tns:"/>`), &p), &qnameErr)
+	assert.ErrorAs(t, xml.Unmarshal([]byte(`<part type="a:b:c"/>`), &p), &qnameErr)
+
+	// Restrictions are generated as structs embedding QName
+	type faultCode struct {
+		xsdtypes.QName
+	}
+	code := faultCode{xsdtypes.QName{Name: xml.Name{Local: "Client"}, Prefix: "soap"}}
+	require.NoError(t, xsdtypes.ResolveQNames(xml.StartElement{Attr: []xml.Attr{{Name: xml.Name{Space: "xmlns", Local: "soap"}, Value: "urn:soap"}}}, &code))
+	assert.Equal(t, xml.Name{Space: "urn:soap", Local: "Client"}, code.Name)
+	start = xml.StartElement{Name: xml.Name{Local: "fault"}}
+	xsdtypes.DeclareQNames(&start, &code)
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Local: "xmlns:soap"}, Value: "urn:soap"}}, start.Attr)
+}
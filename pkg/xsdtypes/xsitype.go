@@ -0,0 +1,75 @@
+package xsdtypes
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// XsiNamespace is the XML Schema instance namespace (xsi:type, xsi:nil, ...).
+const XsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// XsiType returns the type name given by xsi:type attribute of the element, or zero name if there is none.
+//
+// encoding/xml does not expose namespace declarations in scope, so the prefix of the type name is resolved
+// using xmlns attributes of the element itself and of the given enclosing elements only, unless the document
+// is decoded by NamespaceDecoder. The namespace of unresolved names is left empty, and registries fall back
+// to matching these by local name.
+func XsiType(el xml.StartElement, scopes ...xml.StartElement) xml.Name {
+	for _, attr := range el.Attr {
+		if attr.Name.Local != "type" || (attr.Name.Space != XsiNamespace && attr.Name.Space != "xsi") {
+			continue
+		}
+		prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+		if !found {
+			prefix, local = "", prefix
+		}
+		for _, scope := range append([]xml.StartElement{el}, scopes...) {
+			if space, ok := lookupXmlns(scope, prefix); ok {
+				return xml.Name{Space: space, Local: local}
+			}
+		}
+		return xml.Name{Local: local}
+	}
+	return xml.Name{}
+}
+
+func lookupXmlns(el xml.StartElement, prefix string) (string, bool) {
+	for _, attr := range el.Attr {
+		if prefix == "" && attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			return attr.Value, true
+		}
+		if prefix != "" && attr.Name.Space == "xmlns" && attr.Name.Local == prefix {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// SetXsiType adds xsi:type attribute denoting given type to the start element, together with the namespace
+// declarations it needs. The prefix is used to qualify the type name.
+func SetXsiType(start *xml.StartElement, typeName xml.Name, prefix string) {
+	value := typeName.Local
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: XsiNamespace})
+	if typeName.Space != "" {
+		value = prefix + ":" + typeName.Local
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + prefix}, Value: typeName.Space})
+	}
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: value})
+}
+
+// XsiTyped is implemented by the types derived by extension, giving the name used in xsi:type attribute and
+// the prefix it is qualified with.
+type XsiTyped interface {
+	XsiType() (xml.Name, string)
+}
+
+// MarshalXsiTyped encodes value of the element declared with given type. The xsi:type attribute is added only
+// when the value is of a type derived from the declared one. Generated fields of derivation interfaces call it.
+func MarshalXsiTyped(e *xml.Encoder, start xml.StartElement, value any, declared xml.Name) error {
+	if typed, ok := value.(XsiTyped); ok {
+		if name, prefix := typed.XsiType(); name != declared {
+			SetXsiType(&start, name, prefix)
+		}
+	}
+	return e.EncodeElement(value, start)
+}
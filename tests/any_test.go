@@ -0,0 +1,13 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+)
+
+func TestWildcardNamespace(t *testing.T) {
+	dir, _ := generateModule(t, "xsd-examples/valid/any.xsd", xsd2go.Options{})
+	addTestFiles(t, dir, "any", "wc")
+	goCommand(t, dir, "test", "./...")
+}
@@ -0,0 +1,26 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	fsys := exampleFS(t, "catalog")
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:  "user.com/private",
+		OutputDir: "models",
+		FS:        fsys,
+		Catalogs:  []string{"catalog.xml"},
+	}).Generate("order.xsd")
+	require.NoError(t, err)
+
+	order := string(res.Files["order/models.go"])
+	assert.Contains(t, order, `"user.com/private/models/party"`)
+	assert.Contains(t, order, "type Line struct")
+	assert.Contains(t, order, "XmlLang")
+	assert.Contains(t, res.Files, "party/models.go")
+}
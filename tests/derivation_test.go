@@ -0,0 +1,18 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignDerivation(t *testing.T) {
+	dir, res := generateModule(t, "testdata/derivation/circles.xsd", xsd2go.Options{})
+	require.Len(t, res.Files, 2)
+	assert.Contains(t, string(res.Files["cir/models.go"]), "shp.ShapeTypeDerivations.Register(")
+
+	addTestFiles(t, dir, "derivation", "cir")
+	goCommand(t, dir, "test", "./...")
+}
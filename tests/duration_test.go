@@ -0,0 +1,13 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+)
+
+func TestDurationRoundTrip(t *testing.T) {
+	dir, _ := generateModule(t, "xsd-examples/valid/duration.xsd", xsd2go.Options{})
+	addTestFiles(t, dir, "duration", "dur")
+	goCommand(t, dir, "test", "./...")
+}
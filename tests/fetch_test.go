@@ -0,0 +1,85 @@
+package tests_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gocomply/xsd2go/pkg/xsd"
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchRemote(t *testing.T) {
+	// The schemas found in schemas/ are served, while order.xsd refers to these by URL
+	remote := exampleFS(t, "fetch")
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.FileServerFS(remote).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	fsys := fstest.MapFS{
+		"order.xsd": {Data: bytes.ReplaceAll(remote["order.xsd"].Data, []byte("SERVER"), []byte(server.URL))},
+	}
+	generate := func(fetcher xsd.Fetcher) (*xsd2go.Result, error) {
+		return xsd2go.NewGenerator(xsd2go.Options{
+			GoModule:  "user.com/private",
+			OutputDir: "models",
+			FS:        fsys,
+			Fetcher:   fetcher,
+		}).Generate("order.xsd")
+	}
+
+	_, err := generate(nil)
+	require.ErrorIs(t, err, xsd.ErrRemoteNotAllowed)
+
+	cacheDir := t.TempDir()
+	_, err = generate(&xsd.HTTPFetcher{CacheDir: cacheDir, Offline: true})
+	require.ErrorIs(t, err, xsd.ErrCacheMiss)
+
+	res, err := generate(&xsd.HTTPFetcher{CacheDir: cacheDir})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Contains(t, string(res.Files["party/models.go"]), "type Address struct")
+
+	// Subsequent runs are served from the cache
+	server.Close()
+	offline, err := generate(&xsd.HTTPFetcher{CacheDir: cacheDir, Offline: true})
+	require.NoError(t, err)
+	assert.Equal(t, res.Files, offline.Files)
+}
+
+func TestFetchTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	fetcher := &xsd.HTTPFetcher{Timeout: 50 * time.Millisecond}
+	_, err := fetcher.Fetch(server.URL + "/schemas/party.xsd")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFetchMaxSize(t *testing.T) {
+	remote := exampleFS(t, "fetch")
+	server := httptest.NewServer(http.FileServerFS(remote))
+	defer server.Close()
+
+	address := remote["schemas/address.xsd"].Data
+	fetcher := &xsd.HTTPFetcher{MaxSize: int64(len(address))}
+	data, err := fetcher.Fetch(server.URL + "/schemas/address.xsd")
+	require.NoError(t, err)
+	assert.Equal(t, address, data)
+
+	fetcher.MaxSize--
+	_, err = fetcher.Fetch(server.URL + "/schemas/address.xsd")
+	require.ErrorIs(t, err, xsd.ErrSchemaTooLarge)
+}
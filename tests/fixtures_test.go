@@ -0,0 +1,38 @@
+package tests_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// exampleFS loads the files of xsd-examples/fs/DIR directory tree into memory, so that the tests may leave some of
+// these out or adjust them before generating the code.
+func exampleFS(t *testing.T, dir string) fstest.MapFS {
+	t.Helper()
+
+	root := filepath.Join("xsd-examples", "fs", dir)
+	fsys := fstest.MapFS{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		fsys[filepath.ToSlash(name)] = &fstest.MapFile{Data: data}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, fsys)
+	return fsys
+}
@@ -0,0 +1,13 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+)
+
+func TestIdentityConstraints(t *testing.T) {
+	dir, _ := generateModule(t, "xsd-examples/valid/identity.xsd", xsd2go.Options{})
+	addTestFiles(t, dir, "identity", "id")
+	goCommand(t, dir, "test", "./...")
+}
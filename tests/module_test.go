@@ -0,0 +1,62 @@
+package tests_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedModule is the go module the code is generated into by generateModule.
+const generatedModule = "example.com/generated"
+
+// generateModule generates go code for the schema into go module created in temporary directory, so that the code
+// may be built and tested outside of this module. The generated packages are imported as
+// example.com/generated/models/PACKAGE and use the packages of this repository.
+func generateModule(t *testing.T, xsdPath string, opts xsd2go.Options) (string, *xsd2go.Result) {
+	t.Helper()
+
+	repo, err := filepath.Abs("..")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	goMod := fmt.Sprintf("module %s\n\ngo 1.25.0\n\nrequire github.com/gocomply/xsd2go v0.0.0\n\nreplace github.com/gocomply/xsd2go => %s\n",
+		generatedModule, repo)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o600))
+
+	opts.GoModule = generatedModule
+	opts.OutputDir = "models"
+	opts.Output = xsd2go.DirWriter(filepath.Join(dir, opts.OutputDir))
+	res, err := xsd2go.NewGenerator(opts).Generate(xsdPath)
+	require.NoError(t, err)
+	return dir, res
+}
+
+// addTestFiles copies go test files found in testdata directory into the generated package.
+func addTestFiles(t *testing.T, dir, testdata, goPackage string) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("testdata", testdata, "*_test.go"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "models", goPackage, filepath.Base(file)), data, 0o600))
+	}
+}
+
+// goCommand runs go command (e.g. build or test) within the generated module.
+func goCommand(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.CommandContext(t.Context(), "go", args...)
+	cmd.Dir = dir
+	// Everything the generated code needs is found within this repository
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
@@ -0,0 +1,13 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+)
+
+func TestNillableRoot(t *testing.T) {
+	dir, _ := generateModule(t, "xsd-examples/valid/nillable.xsd", xsd2go.Options{})
+	addTestFiles(t, dir, "nillable", "nl")
+	goCommand(t, dir, "test", "./...")
+}
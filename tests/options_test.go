@@ -0,0 +1,57 @@
+package tests_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd"
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionExamples gives the options each schema of xsd-examples/options is generated with.
+var optionExamples = map[string]xsd2go.Options{
+	"timetypes.xsd":      {TimeTypes: true},
+	"precisenumbers.xsd": {PreciseNumbers: true},
+	"binarytypes.xsd":    {BinaryTypes: true},
+	"qnametypes.xsd":     {QNameTypes: true},
+}
+
+func TestOptions(t *testing.T) {
+	for name, opts := range optionExamples {
+		t.Run(name, func(t *testing.T) {
+			xsdPath := filepath.Join("xsd-examples", "options", name)
+			dir, res := generateModule(t, xsdPath, opts)
+			assert.Empty(t, res.Diagnostics)
+			require.Len(t, res.Files, 1)
+
+			expected, err := os.ReadFile(xsdPath + ".out")
+			require.NoError(t, err)
+			for _, actual := range res.Files {
+				assert.Equal(t, strings.ReplaceAll(string(expected), "\r\n", "\n"), string(actual))
+			}
+			goCommand(t, dir, "vet", "./...")
+		})
+	}
+}
+
+func TestLossyNumbers(t *testing.T) {
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:  "user.com/private",
+		OutputDir: "models",
+	}).Generate("xsd-examples/options/precisenumbers.xsd")
+	require.NoError(t, err)
+	assert.Contains(t, string(res.Files["inv/models.go"]), "Rate     *float64")
+
+	var warnings []string
+	for _, diag := range res.Diagnostics {
+		if diag.Severity == xsd.SeverityWarning {
+			warnings = append(warnings, diag.Message)
+		}
+	}
+	assert.Contains(t, warnings, "xsd:decimal is generated as float64, which cannot hold all of its values")
+	assert.Contains(t, warnings, "xsd:positiveInteger is generated as uint64, which cannot hold all of its values")
+}
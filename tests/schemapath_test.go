@@ -0,0 +1,69 @@
+package tests_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/gocomply/xsd2go/pkg/xsd"
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaPath(t *testing.T) {
+	fsys := exampleFS(t, "schemapath")
+
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:        "user.com/private",
+		OutputDir:       "models",
+		FS:              fsys,
+		SchemaPath:      []string{"schemas", "other"},
+		SchemaLocations: map[string]string{"urn:example:line": "other/line-2.0.xsd"},
+	}).Generate("order.xsd")
+	require.NoError(t, err)
+	assert.Empty(t, res.Diagnostics)
+
+	order := string(res.Files["order/models.go"])
+	assert.Contains(t, order, `"user.com/private/models/party"`)
+	assert.Contains(t, order, `"user.com/private/models/line_2_0"`)
+	assert.Contains(t, string(res.Files["party/models.go"]), "type Name string")
+}
+
+func TestSchemaPathAmbiguous(t *testing.T) {
+	fsys := exampleFS(t, "schemapath")
+	delete(fsys, "schemas/README.txt")
+	delete(fsys, "other/unrelated/not-a-schema.xsd")
+	delete(fsys, "other/unrelated/broken-document.xsd")
+
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:   "user.com/private",
+		OutputDir:  "models",
+		FS:         fsys,
+		SchemaPath: []string{"schemas", "other"},
+	}).Generate("order.xsd")
+	require.NoError(t, err)
+	require.Len(t, res.Diagnostics, 1)
+	assert.Equal(t, xsd.SeverityWarning, res.Diagnostics[0].Severity)
+	assert.Equal(t, "other/line-1.0.xsd", res.Diagnostics[0].File)
+}
+
+func TestSchemaPathMissing(t *testing.T) {
+	fsys := fstest.MapFS{
+		"order.xsd": exampleFS(t, "schemapath")["order.xsd"],
+	}
+
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:  "user.com/private",
+		OutputDir: "models",
+		FS:        fsys,
+	}).Generate("order.xsd")
+	require.Error(t, err)
+
+	warnings := []string{}
+	for _, diag := range res.Diagnostics {
+		if diag.Severity == xsd.SeverityWarning {
+			warnings = append(warnings, diag.Message)
+		}
+	}
+	assert.Contains(t, warnings, "no schema found for xsd:import of namespace 'urn:example:party', consider adding its directory to the schema path")
+}
@@ -1,13 +1,16 @@
 package tests_test
 
 import (
+	"bytes"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gocomply/xsd2go/pkg/xsd"
 	"github.com/gocomply/xsd2go/pkg/xsd2go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,3 +61,68 @@ func locateGeneratedFile(outputDir string) (string, error) {
 	}
 	return golangFiles[0], nil
 }
+
+func TestInvalid(t *testing.T) {
+	xsdFiles, err := filepath.Glob("xsd-examples/invalid/*.xsd")
+	require.NoError(t, err)
+	assert.NotEmpty(t, xsdFiles)
+
+	for _, xsdPath := range xsdFiles {
+		diagnostics, err := xsd2go.ConvertWithDiagnostics(xsdPath, "user.com/private", t.TempDir(), nil)
+		var compileErr *xsd.CompileError
+		require.ErrorAs(t, err, &compileErr, xsdPath)
+		assert.Equal(t, xsdPath, compileErr.File)
+
+		actual := ""
+		for _, diag := range diagnostics {
+			actual += diag.String() + "\n"
+		}
+		expected, err := os.ReadFile(xsdPath + ".err")
+		require.NoError(t, err)
+		assert.Equal(t, strings.ReplaceAll(string(expected), "\r\n", "\n"), actual)
+	}
+}
+
+func TestConvertDoesNotLog(t *testing.T) {
+	var logged bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logged, nil)))
+
+	require.NoError(t, xsd2go.Convert("xsd-examples/valid/simple.xsd", "user.com/private", t.TempDir(), nil))
+	assert.Empty(t, logged.String())
+}
+
+func TestGenerateInMemory(t *testing.T) {
+	xsdPath := "xsd-examples/valid/simple.xsd"
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:  "user.com/private",
+		OutputDir: "models",
+		FileName:  "simple.go",
+	}).Generate(xsdPath)
+	require.NoError(t, err)
+	for _, diag := range res.Diagnostics {
+		assert.Equal(t, xsd.SeverityWarning, diag.Severity)
+	}
+	require.Len(t, res.Files, 1)
+
+	expected, err := os.ReadFile(xsdPath + ".out")
+	require.NoError(t, err)
+	for name, actual := range res.Files {
+		assert.Equal(t, "simple_schema/simple.go", name)
+		assert.Equal(t, strings.ReplaceAll(string(expected), "\r\n", "\n"), string(actual))
+	}
+}
+
+func TestGenerateFromFS(t *testing.T) {
+	fsys := exampleFS(t, "generate")
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:  "user.com/private",
+		OutputDir: "models",
+		FS:        fsys,
+	}).Generate("schemas/order.xsd")
+	require.NoError(t, err)
+	require.Len(t, res.Files, 2)
+	assert.Contains(t, string(res.Files["order/models.go"]), "type Line struct")
+	assert.Contains(t, string(res.Files["order/models.go"]), `"user.com/private/models/common"`)
+	assert.Contains(t, string(res.Files["common/models.go"]), "type Party struct")
+}
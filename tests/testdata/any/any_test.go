@@ -0,0 +1,42 @@
+package wc_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"example.com/generated/models/wc"
+	"github.com/gocomply/xsd2go/pkg/xsdtypes"
+)
+
+func TestAttributeWildcard(t *testing.T) {
+	for doc, valid := range map[string]bool{
+		`<envelope xmlns="https://any.example.com/" xmlns:ext="urn:ext" ext:a="1"><header/><body/></envelope>`:                true,
+		`<envelope xmlns="https://any.example.com/" a="1"><header/><body/></envelope>`:                                        false,
+		`<envelope xmlns="https://any.example.com/" xmlns:wc="https://any.example.com/" wc:a="1"><header/><body/></envelope>`: false,
+	} {
+		var envelope wc.Envelope
+		if err := xml.Unmarshal([]byte(doc), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		err := envelope.Validate()
+		if valid && err != nil {
+			t.Fatalf("%s: %v", doc, err)
+		}
+		if !valid && !errors.Is(err, xsdtypes.ErrWildcardNamespace) {
+			t.Fatalf("%s: wildcard namespace is not validated: %v", doc, err)
+		}
+	}
+}
+
+func TestExtendedAttributeWildcard(t *testing.T) {
+	// Wildcard of the extension allows any namespace, the union with the wildcard of the base type does too
+	var envelope wc.ExtendedEnvelopeType
+	doc := `<envelope xmlns="https://any.example.com/" a="1"><header/><body/><trailer/></envelope>`
+	if err := xml.Unmarshal([]byte(doc), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if err := envelope.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,63 @@
+package cir_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"example.com/generated/models/cir"
+	"example.com/generated/models/shp"
+)
+
+func TestForeignDerivation(t *testing.T) {
+	doc := `<drawing xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:cir="urn:example:circles">` +
+		`<shape id="1"/><shape xsi:type="cir:CircleType" id="2"><radius>3</radius></shape></drawing>`
+	var drawing shp.Drawing
+	if err := xml.Unmarshal([]byte(doc), &drawing); err != nil {
+		t.Fatal(err)
+	}
+	if len(drawing.Shape) != 2 {
+		t.Fatalf("unexpected shapes %+v", drawing.Shape)
+	}
+	if shape, ok := drawing.Shape[0].(*shp.ShapeType); !ok || shape.Id != "1" {
+		t.Fatalf("unexpected shape %+v", drawing.Shape[0])
+	}
+	if circle, ok := drawing.Shape[1].(*cir.CircleType); !ok || circle.Id != "2" || circle.Radius != 3 {
+		t.Fatalf("type registered by init of the derived package is not decoded %+v", drawing.Shape[1])
+	}
+
+	out, err := xml.Marshal(drawing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<drawing><shape id="1"></shape><shape xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+		`xmlns:cir="urn:example:circles" xsi:type="cir:CircleType" id="2"><radius>3</radius></shape></drawing>`
+	if string(out) != expected {
+		t.Fatalf("unexpected document %s", out)
+	}
+	var again shp.Drawing
+	if err := xml.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := again.Shape[1].(*cir.CircleType); !ok {
+		t.Fatalf("derived type does not survive round trip %+v", again.Shape[1])
+	}
+}
+
+func TestDeclaredType(t *testing.T) {
+	for _, c := range []struct {
+		highlight cir.CircleTypeDerivation
+		expected  string
+	}{
+		{&cir.CircleType{Radius: 1}, `<frame><highlight><radius>1</radius></highlight></frame>`},
+		{&cir.ColoredCircleType{Color: "red", Radius: 1}, `<frame><highlight xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+			`xmlns:cir="urn:example:circles" xsi:type="cir:ColoredCircleType" color="red"><radius>1</radius></highlight></frame>`},
+	} {
+		out, err := xml.Marshal(cir.Frame{Highlight: c.highlight})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != c.expected {
+			t.Fatalf("unexpected document %s", out)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package dur_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/generated/models/dur"
+)
+
+func TestRoundTrip(t *testing.T) {
+	doc := `<task xmlns="https://duration.example.com/" reminder="-P1DT2H"><length>PT1H30M</length><term>P1Y6M</term><intervals>P1M PT0.5S</intervals></task>`
+	var task dur.Task
+	if err := xml.Unmarshal([]byte(doc), &task); err != nil {
+		t.Fatal(err)
+	}
+	if !task.Reminder.Negative || task.Reminder.Days != 1 || task.Reminder.Time != 2*time.Hour ||
+		task.Length.Time != 90*time.Minute || task.Term.Years != 1 || task.Term.Months != 6 ||
+		len(*task.Intervals) != 2 || (*task.Intervals)[1].Time != 500*time.Millisecond {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if err := task.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := xml.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != strings.Replace(doc, ` xmlns="https://duration.example.com/"`, "", 1) {
+		t.Fatalf("unexpected document %s", out)
+	}
+}
+
+func TestRestriction(t *testing.T) {
+	var length dur.Length
+	if err := length.UnmarshalText([]byte("PT8H")); err != nil {
+		t.Fatal(err)
+	}
+	if err := length.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := length.UnmarshalText([]byte("P1D")); err != nil {
+		t.Fatal(err)
+	}
+	if length.Validate() == nil {
+		t.Fatal("maxInclusive facet is not validated")
+	}
+	if length.UnmarshalText([]byte("P1M")) == nil {
+		t.Fatal("dayTimeDuration accepts months")
+	}
+	var term dur.Task
+	if xml.Unmarshal([]byte(`<task><length>PT1H</length><term>P1D</term></task>`), &term) == nil {
+		t.Fatal("yearMonthDuration accepts days")
+	}
+}
@@ -0,0 +1,26 @@
+package id_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"example.com/generated/models/id"
+	"github.com/gocomply/xsd2go/pkg/xsdtypes"
+)
+
+func TestLoneChildField(t *testing.T) {
+	doc := `<catalog><product id="a" variant="x"><name>Pen</name></product><product id="b" variant="x"><name>Pen</name></product></catalog>`
+	var catalog id.Catalog
+	if err := xml.Unmarshal([]byte(doc), &catalog); err != nil {
+		t.Fatal(err)
+	}
+	err := id.ValidateIdentityConstraints(catalog)
+	if !errors.Is(err, xsdtypes.ErrDuplicateKey) || errors.Is(err, xsdtypes.ErrMissingKeyField) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.Contains(err.Error(), "/catalog/product[2]: duplicate value 'Pen, x' of unique 'productNameUnique'") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
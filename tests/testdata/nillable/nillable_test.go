@@ -0,0 +1,41 @@
+package nl_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"example.com/generated/models/nl"
+)
+
+func TestNillableRoot(t *testing.T) {
+	var note nl.Note
+	if err := xml.Unmarshal([]byte(`<note xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"/>`), &note); err != nil {
+		t.Fatal(err)
+	}
+	if !note.Nil || note.Text != "" {
+		t.Fatalf("element marked by xsi:nil is not decoded as nil %+v", note)
+	}
+
+	out, err := xml.Marshal(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<note xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></note>`
+	if string(out) != expected {
+		t.Fatalf("unexpected output %s", out)
+	}
+
+	note = nl.Note{}
+	if err := xml.Unmarshal([]byte(`<note>hello</note>`), &note); err != nil {
+		t.Fatal(err)
+	}
+	if note.Nil || note.Text != "hello" {
+		t.Fatalf("unexpected note %+v", note)
+	}
+	if out, err = xml.Marshal(note); err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `<note>hello</note>` {
+		t.Fatalf("unexpected output %s", out)
+	}
+}
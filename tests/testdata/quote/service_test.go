@@ -0,0 +1,64 @@
+package quote_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/generated/models/common"
+	"example.com/generated/models/quote"
+	"github.com/gocomply/xsd2go/pkg/soap"
+)
+
+type service struct {
+	subscribed []common.Symbol
+}
+
+func (s *service) GetQuote(_ context.Context, req *quote.GetQuote) (*quote.GetQuoteResponse, error) {
+	if req.Symbol != "ACME" {
+		return nil, &soap.Fault{Code: "soap:Client", Reason: "unknown symbol", Detail: &quote.UnknownSymbol{Symbol: req.Symbol}}
+	}
+	return &quote.GetQuoteResponse{Price: 12.5}, nil
+}
+
+func (s *service) Subscribe(_ context.Context, req *quote.Subscribe) error {
+	s.subscribed = append(s.subscribed, req.Symbol)
+	return nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	svc := &service{}
+	bindings := []struct {
+		handler http.Handler
+		client  func(url string) quote.StockQuotePortType
+	}{
+		{quote.NewStockQuoteSoapHandler(svc), func(url string) quote.StockQuotePortType { return quote.NewStockQuoteSoapClient(url, nil) }},
+		{quote.NewStockQuoteSoap12Handler(svc), func(url string) quote.StockQuotePortType { return quote.NewStockQuoteSoap12Client(url, nil) }},
+	}
+	for _, b := range bindings {
+		server := httptest.NewServer(b.handler)
+		client := b.client(server.URL)
+
+		resp, err := client.GetQuote(context.Background(), &quote.GetQuote{Symbol: "ACME"})
+		if err != nil || resp.Price != 12.5 {
+			t.Fatalf("unexpected response %v, %v", resp, err)
+		}
+		_, err = client.GetQuote(context.Background(), &quote.GetQuote{Symbol: "XYZ"})
+		var fault *soap.Fault
+		if !errors.As(err, &fault) {
+			t.Fatalf("expected fault, got %v", err)
+		}
+		if detail, ok := fault.Detail.(*quote.UnknownSymbol); !ok || detail.Symbol != "XYZ" {
+			t.Fatalf("unexpected fault detail %#v", fault.Detail)
+		}
+		if err := client.Subscribe(context.Background(), &quote.Subscribe{Symbol: "ACME"}); err != nil {
+			t.Fatal(err)
+		}
+		server.Close()
+	}
+	if len(svc.subscribed) != 2 {
+		t.Fatalf("unexpected subscriptions %v", svc.subscribed)
+	}
+}
@@ -0,0 +1,35 @@
+package un_test
+
+import (
+	"testing"
+
+	"example.com/generated/models/un"
+)
+
+func TestMemberFacets(t *testing.T) {
+	var code un.CodeOrLabel
+	for text, expected := range map[string]string{"12345": "zip", "7": "small", "abc": "string", "42": "string"} {
+		if err := code.UnmarshalText([]byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		_, zip := code.AsZip()
+		_, small := code.AsSmall()
+		_, str := code.AsString()
+		if actual := map[bool]string{zip: "zip", small: "small", str: "string"}[true]; actual != expected {
+			t.Fatalf("%q is decoded as %s member, expected %s", text, actual, expected)
+		}
+		if err := code.Validate(); err != nil {
+			t.Fatalf("%q is not valid: %v", text, err)
+		}
+	}
+}
+
+func TestMemberWhiteSpace(t *testing.T) {
+	var released un.DateOrUnknown
+	if err := released.UnmarshalText([]byte(" unknown\n")); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := released.AsUnknownEnumeration(); !ok || value != un.UnknownEnumerationUnknown {
+		t.Fatalf("collapsed enumeration value is not recognized %+v", released)
+	}
+}
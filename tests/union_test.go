@@ -0,0 +1,13 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+)
+
+func TestUnionMembers(t *testing.T) {
+	dir, _ := generateModule(t, "xsd-examples/valid/union.xsd", xsd2go.Options{})
+	addTestFiles(t, dir, "union", "un")
+	goCommand(t, dir, "test", "./...")
+}
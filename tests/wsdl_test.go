@@ -0,0 +1,101 @@
+package tests_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gocomply/xsd2go/pkg/xsd"
+	"github.com/gocomply/xsd2go/pkg/xsd2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWsdl(t *testing.T) {
+	fsys := exampleFS(t, "wsdl")
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:     "user.com/private",
+		OutputDir:    "models",
+		FS:           fsys,
+		PackageNames: map[string]string{"urn:example:quote": "quote", "urn:example:common": "common"},
+	}).Generate("services/quote.wsdl")
+	require.NoError(t, err)
+	assert.Empty(t, res.Diagnostics)
+
+	quote := string(res.Files["quote/models.go"])
+	assert.Contains(t, quote, `"user.com/private/models/common"`)
+	assert.Contains(t, quote, "type GetQuote struct")
+	assert.Contains(t, quote, "type GetQuoteResponse struct")
+	assert.Contains(t, quote, "common.Symbol")
+	assert.Contains(t, string(res.Files["common/models.go"]), "type Symbol string")
+}
+
+func TestWsdlDiagnostics(t *testing.T) {
+	fsys := exampleFS(t, "wsdl")
+	res, err := xsd2go.NewGenerator(xsd2go.Options{
+		GoModule:  "user.com/private",
+		OutputDir: "models",
+		FS:        fsys,
+	}).Generate("broken.wsdl")
+	require.Error(t, err)
+	require.Len(t, res.Diagnostics, 1)
+	assert.Equal(t, xsd.Diagnostic{
+		Severity: xsd.SeverityError, File: "broken.wsdl", Line: 5, Column: 7,
+		Path: "element[@name=order]", Message: "not implemented: type xsd:missing",
+	}, res.Diagnostics[0])
+}
+
+func TestWsdlWithoutSchemas(t *testing.T) {
+	fsys := exampleFS(t, "wsdl")
+	for _, file := range []string{"empty.wsdl", "other.wsdl"} {
+		_, err := xsd2go.NewGenerator(xsd2go.Options{
+			GoModule:  "user.com/private",
+			OutputDir: "models",
+			FS:        fsys,
+		}).Generate(file)
+		assert.Error(t, err, file)
+	}
+}
+
+func TestWsdlService(t *testing.T) {
+	fsys := exampleFS(t, "wsdl")
+	dir, res := generateModule(t, "services/quote.wsdl", xsd2go.Options{
+		FS:           fsys,
+		PackageNames: map[string]string{"urn:example:quote": "quote", "urn:example:common": "common"},
+	})
+	service := string(res.Files["quote/service.go"])
+	assert.Contains(t, service, "type StockQuotePortType interface")
+	assert.Contains(t, service, `StockQuotePortAddress = "http://example.com/stockquote"`)
+
+	addTestFiles(t, dir, "quote", "quote")
+	goCommand(t, dir, "test", "./...")
+}
+
+func TestWsdlServiceUnsupported(t *testing.T) {
+	quote := exampleFS(t, "wsdl")["services/quote.wsdl"].Data
+	fsys := fstest.MapFS{
+		"quote.wsdl": {Data: bytes.ReplaceAll(quote, []byte(`<soap:binding style="document"`), []byte(`<soap:binding style="rpc"`))},
+	}
+	dir, res := generateModule(t, "quote.wsdl", xsd2go.Options{
+		FS:           fsys,
+		PackageNames: map[string]string{"urn:example:quote": "quote", "urn:example:common": "common"},
+	})
+
+	messages := []string{}
+	for _, diag := range res.Diagnostics {
+		assert.Equal(t, xsd.SeverityWarning, diag.Severity)
+		assert.Equal(t, "quote.wsdl", diag.File)
+		messages = append(messages, diag.Message)
+	}
+	assert.Equal(t, []string{
+		"operation GetQuote is left out: not implemented: rpc style",
+		"operation Subscribe is left out: not implemented: rpc style",
+	}, messages)
+	service := string(res.Files["quote/service.go"])
+	assert.NotContains(t, service, "stockQuoteSoapGetQuoteOperation")
+	assert.Contains(t, service, "stockQuoteSoap12GetQuoteOperation")
+	assert.Contains(t, service, `return nil, fmt.Errorf("%w: GetQuote", soap.ErrUnsupported)`)
+
+	// The client of rpc binding still implements the port type
+	goCommand(t, dir, "build", "./...")
+}